@@ -9,9 +9,11 @@ import (
 	"io/ioutil"
 	"math"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/ianremmler/trigo"
+	"github.com/ianremmler/trigo/store"
 	"golang.org/x/mobile/app"
 	"golang.org/x/mobile/asset"
 	"golang.org/x/mobile/event/lifecycle"
@@ -29,7 +31,10 @@ const (
 	transitionTime = 1 * time.Second
 	transitionRate = 60 // fps
 	charsPerRow    = 16
-	stateFile      = "/data/data/org.remmler.TriGo/state"
+	drillChoices   = 6
+	dataDir        = "/data/data/org.remmler.TriGo/"
+	stateFile      = dataDir + "state"
+	playerName     = "player"
 )
 
 var colors = [][]float32{
@@ -49,12 +54,23 @@ var shapes = []shape{
 	{verts: hexVerts},
 }
 
+var (
+	attrNames = []string{"NUM", "COLOR", "SHAPE", "FILL"}
+	valNames  = [][]string{
+		{"1", "2", "3"},
+		{"RED", "GREEN", "BLUE"},
+		{"SQUARE", "TRI", "HEX"},
+		{"OPEN", "STRIPE", "SOLID"},
+	}
+)
+
 type cardState int
 
 const (
 	normal cardState = iota
 	selected
 	invalid
+	hinted
 	fadeOut
 	fadeIn
 )
@@ -176,7 +192,16 @@ var (
 	state     gameState
 	matches   int
 	deckSize  int
+	modes     = trigo.StdModes()
+	result    trigo.Result
+	endText   string
+	puzzle    *trigo.Puzzle
+	stats     *store.Store
+	profile   *store.Profile
+	drill     *trigo.Drill
 	candidate = map[int]struct{}{}
+	hints     = map[int]struct{}{}
+	message   string
 
 	transitionParam float32
 
@@ -187,6 +212,7 @@ var (
 	cardColor    = []float32{1, 1, 1, 1}
 	selectColor  = []float32{0, 1, 1, 0.25}
 	invalidColor = []float32{1, 0, 0, 0.25}
+	hintColor    = []float32{1, 1, 0, 0.25}
 	textColor    = []float32{0, 1, 1, 1}
 )
 
@@ -194,6 +220,9 @@ type TransitionEvent struct {
 	T float32
 }
 
+// TickEvent is sent every second to keep the clock display current.
+type TickEvent struct{}
+
 type prog struct {
 	p gl.Program
 	u map[string]gl.Uniform
@@ -219,6 +248,11 @@ func newProg(ctx gl.Context, vertShader, fragShader string, uni []string, attrib
 func main() {
 	app.Main(func(a app.App) {
 		ap = a
+		go func() {
+			for range time.Tick(time.Second) {
+				ap.Send(TickEvent{})
+			}
+		}()
 		for evt := range ap.Events() {
 			switch evt := ap.Filter(evt).(type) {
 			case lifecycle.Event:
@@ -237,6 +271,11 @@ func main() {
 			case touch.Event:
 				handleTouch(evt)
 				draw()
+			case TickEvent:
+				if glctx != nil && state == play {
+					checkTime()
+					draw()
+				}
 			case TransitionEvent:
 				transitionParam = evt.T
 				if evt.T >= 1 { // transitionion complete
@@ -256,11 +295,18 @@ func start() {
 
 	if stateData, err := ioutil.ReadFile(stateFile); err == nil {
 		tri = trigo.NewFromSavedState(stateData)
+		tri.Resume()
 	} else {
 		tri = trigo.NewStd()
 		tri.Shuffle()
 		tri.Deal()
 	}
+	tri.SetScoring(trigo.StdScoring()...)
+	if st, err := store.Open(dataDir); err == nil {
+		if p, err := st.Profile(playerName); err == nil {
+			stats, profile = st, p
+		}
+	}
 	field = tri.Field()
 	deckSize = tri.DeckSize()
 	matches = tri.MatchesFound()
@@ -342,6 +388,7 @@ func setupTextProg() error {
 }
 
 func stop() {
+	tri.Pause()
 	if stateData, err := tri.State(); err == nil {
 		ioutil.WriteFile(stateFile, stateData, 0644)
 	}
@@ -383,13 +430,30 @@ func handleTouch(evt touch.Event) {
 	c := int(math.Floor(float64(s*w/fw-marginX) * float64(cols)))
 	r := int(math.Floor(float64(t*h/fh-marginY) * float64(rows)))
 
+	if r < 0 {
+		// tapped above the field
+		if puzzle == nil && drill == nil {
+			showHint()
+		}
+		return
+	}
+	if r >= rows {
+		// tapped below the field
+		nextMode()
+		return
+	}
+
 	idx := -1
 	if r >= 0 && r < rows && c >= 0 && c < cols {
 		idx = 3*c + (2 - r)
 	}
 
 	if idx >= 0 && idx < len(field) && !field[idx].Blank {
-		updateCandidate(idx)
+		if drill != nil {
+			answerDrill(idx)
+		} else {
+			updateCandidate(idx)
+		}
 	}
 }
 
@@ -399,6 +463,7 @@ func updateCandidate(idx int) {
 	} else if len(candidate) < 3 {
 		candidate[idx] = struct{}{}
 	}
+	message = ""
 	if len(candidate) < 3 {
 		return
 	}
@@ -406,23 +471,226 @@ func updateCandidate(idx int) {
 	for idx := range candidate {
 		check = append(check, idx)
 	}
-	if !tri.IsMatch(check) {
+	if puzzle != nil {
+		claimPuzzle(check)
+		return
+	}
+	if !tri.Claim(check) {
+		message = explain(tri.Explain(check))
 		return
 	}
 	// still here... we got a match!
 	newState := match
-	tri.Remove(check)
 	matches = tri.MatchesFound()
 	tri.Deal()
-	if tri.FieldMatches() == 0 {
+	for !tri.Over() && tri.FindMatch() == nil {
+		tri.DeclareNoMatch()
+	}
+	if tri.Over() {
 		// we won!
 		newState = win
+		finish()
 		tri.Shuffle()
 		tri.Deal()
 	}
 	startTransition(newState)
 }
 
+// finish records the result of the finished game, and sets the text to show
+// at the end.
+func finish() {
+	result = tri.Result()
+	endText = strings.ToUpper(result.Headline())
+	if stats == nil || profile == nil {
+		return
+	}
+	profile.Weaknesses.Add(tri.Report())
+	if isBest, err := stats.AddResult(profile, result, time.Now()); err == nil && isBest && profile.Games > 1 {
+		endText = "NEW BEST! " + endText
+	}
+}
+
+// checkTime ends a timed game whose time has run out.
+func checkTime() {
+	if puzzle != nil || drill != nil || !tri.TimedOut() {
+		return
+	}
+	finish()
+	tri.Shuffle()
+	tri.Deal()
+	startTransition(win)
+}
+
+// nextMode starts a new game in the next mode, with a puzzle and then the
+// drill after the last.
+func nextMode() {
+	mode := modes[0]
+	switch {
+	case drill != nil:
+		drill = nil
+	case puzzle != nil:
+		puzzle = nil
+		drill, _ = trigo.NewDrill(trigo.StdConfig(), drillChoices, rand.Int63())
+		drill.Next()
+		message = ""
+		startTransition(newGame)
+		return
+	case tri.Mode().Kind == modes[len(modes)-1].Kind:
+		tri.Pause()
+		puzzle = newPuzzle(trigo.DailySeed(time.Now()))
+		hints = map[int]struct{}{}
+		startTransition(newGame)
+		return
+	default:
+		for i := range modes {
+			if modes[i].Kind == tri.Mode().Kind {
+				mode = modes[i+1]
+			}
+		}
+	}
+	tri.SetMode(mode)
+	tri.Shuffle()
+	tri.Deal()
+	matches = 0
+	deckSize = tri.DeckSize()
+	hints = map[int]struct{}{}
+	startTransition(newGame)
+}
+
+// modeLabel returns the mode name and its clock, if it is timed.
+func modeLabel() string {
+	label := strings.ToUpper(tri.Mode().Kind.String())
+	switch tri.Mode().Kind {
+	case trigo.Blitz, trigo.Survival:
+		label += " " + clockText(tri.TimeLeft())
+	case trigo.TimeAttack:
+		label += " " + clockText(tri.Elapsed())
+	}
+	return label
+}
+
+// clockText formats d as minutes and seconds.
+func clockText(d time.Duration) string {
+	secs := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// newPuzzle returns a generated puzzle, falling back to a freshly dealt field
+// if none is found.
+func newPuzzle(seed int64) *trigo.Puzzle {
+	if p, err := trigo.GeneratePuzzle(trigo.StdPuzzleSpec(), seed); err == nil {
+		return p
+	}
+	t := trigo.NewStd()
+	t.Shuffle()
+	t.Deal()
+	return t.Puzzle()
+}
+
+// claimPuzzle records check as a match found in the puzzle, ending it when
+// every match has been found.
+func claimPuzzle(check []int) {
+	switch err := puzzle.Claim(check); err {
+	case nil:
+		candidate = map[int]struct{}{}
+		message = fmt.Sprintf("FOUND %d OF %d", len(puzzle.Found()), puzzle.NumMatches())
+	case trigo.ErrAlreadyFound:
+		message = "ALREADY FOUND"
+		return
+	default:
+		message = explain(puzzle.Explain(check))
+		return
+	}
+	if puzzle.Done() {
+		endText = fmt.Sprintf("FOUND ALL %d MATCHES", puzzle.NumMatches())
+		puzzle = newPuzzle(rand.Int63())
+		startTransition(win)
+	}
+}
+
+// answerDrill answers the drill question with the card in field slot idx, and
+// asks the next.
+func answerDrill(idx int) {
+	choice := idx - 3
+	if choice < 0 {
+		return
+	}
+	right, took := drill.Answer(choice)
+	if right {
+		message = fmt.Sprintf("RIGHT IN %.1fS", took.Seconds())
+	} else {
+		message = explain(drill.Explain(choice))
+	}
+	drill.Next()
+	field = currentField()
+}
+
+// drillField lays out the drill question: the given cards in the first
+// column, and the choices after.
+func drillField() []trigo.Card {
+	q := drill.Question()
+	field := make([]trigo.Card, 3+len(q.Choices))
+	for i := range field[:3] {
+		field[i] = trigo.Card{Blank: true}
+	}
+	for i, id := range q.Cards {
+		field[2-i] = drill.Card(id)
+	}
+	for i, id := range q.Choices {
+		field[3+i] = drill.Card(id)
+	}
+	return field
+}
+
+// currentField returns the cards of the drill or puzzle, if there is one, or
+// the game.
+func currentField() []trigo.Card {
+	if drill != nil {
+		return drillField()
+	}
+	if puzzle != nil {
+		return puzzle.Field()
+	}
+	return tri.Field()
+}
+
+// showHint reveals the next level of hint for the field.
+func showHint() {
+	hint := tri.Hint()
+	for _, idx := range hint.Slots {
+		hints[idx] = struct{}{}
+	}
+	switch {
+	case !hint.Exists:
+		message = "NO MATCHES"
+	case len(hint.Slots) == 0:
+		message = "THERE IS A MATCH"
+	default:
+		message = ""
+	}
+}
+
+// explain briefly describes the first attribute that keeps a candidate from
+// being a match, given its checks.
+func explain(checks []trigo.AttrCheck, err error) string {
+	if err != nil {
+		return ""
+	}
+	for _, c := range checks {
+		if c.Pass() {
+			continue
+		}
+		msg := attrNames[c.Attr] + ":"
+		for val, n := range c.Counts(len(valNames[c.Attr])) {
+			if n > 0 {
+				msg += fmt.Sprintf(" %d %s", n, valNames[c.Attr][val])
+			}
+		}
+		return msg
+	}
+	return ""
+}
+
 func startTransition(newState gameState) {
 	if newState == play {
 		return
@@ -448,14 +716,16 @@ func updateState() {
 	}
 
 	oldFieldSize := len(field)
-	field = tri.Field()
+	field = currentField()
 	switch state {
 	case match:
 		deckSize = tri.DeckSize()
+		hints = map[int]struct{}{}
 		startTransition(deal)
 	case win:
 		matches = 0
 		deckSize = tri.DeckSize()
+		hints = map[int]struct{}{}
 		startTransition(endGameIn)
 	case endGameIn:
 	case endGameOut:
@@ -463,6 +733,7 @@ func updateState() {
 	default:
 		state = play
 		candidate = map[int]struct{}{}
+		message = ""
 	}
 	if state != deal {
 		return
@@ -536,6 +807,8 @@ func drawCard(mat *f32.Mat4, card *trigo.Card, st cardState) {
 		glctx.Uniform4f(cardProg.u["color"], 0, 0, 0, 1-transitionParam)
 	case selected:
 		glctx.Uniform4fv(cardProg.u["color"], selectColor)
+	case hinted:
+		glctx.Uniform4fv(cardProg.u["color"], hintColor)
 	case invalid:
 		glctx.Uniform4fv(cardProg.u["color"], invalidColor)
 	}
@@ -613,6 +886,8 @@ func drawField() {
 				default:
 					cardSt = invalid
 				}
+			} else if _, ok := hints[i]; ok && state == play {
+				cardSt = hinted
 			}
 		}
 		drawCard(&cardMat, &field[i], cardSt)
@@ -627,16 +902,36 @@ func drawField() {
 		color[3] = 1 - transitionParam
 	}
 
+	text := fmt.Sprintf("DECK: %d SCORE: %d", deckSize, tri.Score())
+	switch {
+	case drill != nil:
+		st := drill.Stats()
+		text = fmt.Sprintf("STREAK: %d BEST: %d", st.Streak, st.BestStreak)
+	case puzzle != nil:
+		text = fmt.Sprintf("FOUND: %d OF %d", len(puzzle.Found()), puzzle.NumMatches())
+	}
+	scale := fitTextScale(text, w)
 	textMat.Translate(&textMat, -0.5*fw, 0.5*fh, 0)
-	textMat.Scale(&textMat, w/charsPerRow, w/charsPerRow, 1)
+	textMat.Scale(&textMat, scale, scale, 1)
 	textMat.Translate(&textMat, 0, 0.5, 1)
-	drawText(fmt.Sprintf("DECK: %d", deckSize), textMat, color)
+	drawText(text, textMat, color)
 
+	text = fmt.Sprintf("%s MATCHES: %d", modeLabel(), matches)
+	switch {
+	case drill != nil:
+		text = "DRILL"
+	case puzzle != nil:
+		text = "PUZZLE " + strings.ToUpper(puzzle.Difficulty().Level().String())
+	}
+	if message != "" {
+		text = message
+	}
+	scale = fitTextScale(text, w)
 	textMat = mat
 	textMat.Translate(&textMat, -0.5*fw, -0.5*fh, 0)
-	textMat.Scale(&textMat, w/charsPerRow, w/charsPerRow, 1)
+	textMat.Scale(&textMat, scale, scale, 1)
 	textMat.Translate(&textMat, 0, -1.5, 1)
-	drawText(fmt.Sprintf("MATCHES: %d", matches), textMat, color)
+	drawText(text, textMat, color)
 }
 
 func drawEnd() {
@@ -662,6 +957,26 @@ func drawEnd() {
 		}
 		drawText(msg[i], textMat, color)
 	}
+
+	text := endText
+	textMat := mat
+	textMat.Translate(&textMat, 0, -3.5, 0)
+	textMat.Scale(&textMat, 3/float32(len(text)), 3/float32(len(text)), 1)
+	color := append([]float32(nil), textColor...)
+	color[3] = transitionParam
+	if state == endGameOut {
+		color[3] = 1 - transitionParam
+	}
+	drawText(text, textMat, color)
+}
+
+// fitTextScale returns the character size for a line of text across a view of
+// width w, shrinking it if the text won't fit.
+func fitTextScale(text string, w float32) float32 {
+	if len(text) > charsPerRow {
+		return w / float32(len(text))
+	}
+	return w / charsPerRow
 }
 
 // drawText draws text in the position and orientation defined by mat
@@ -0,0 +1,171 @@
+package trigo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSymmetries limits the size of the symmetry group Canonical searches.
+const maxSymmetries = 1 << 20
+
+// ErrTooSymmetric is returned when a configuration has too many symmetries to
+// search for a canonical form.
+var ErrTooSymmetric = errors.New("trigo: too many symmetries to search")
+
+// Symmetries returns the number of ways to relabel the cards of the
+// configuration by swapping attributes and permuting the values of each
+// attribute, or -1 if there are too many to search.  Relabeling cards never
+// changes which of them make a match.
+func (c Config) Symmetries() int {
+	n := 1
+	mul := func(k int) {
+		if n < 0 || n > maxSymmetries/k {
+			n = -1
+			return
+		}
+		n *= k
+	}
+	for i := 2; i <= c.NumAttrs; i++ {
+		mul(i)
+	}
+	for j := 0; j < c.NumAttrs; j++ {
+		for i := 2; i <= c.NumAttrVals; i++ {
+			mul(i)
+		}
+	}
+	return n
+}
+
+// Canonical returns the canonical form of a collection of cards: the sorted
+// card IDs that come first among all relabelings of the cards.  Collections
+// that differ only by swapping attributes or permuting attribute values have
+// the same canonical form.
+func Canonical(c Config, cards []int) ([]int, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.Symmetries() < 0 {
+		return nil, ErrTooSymmetric
+	}
+	numCards := c.NumCards()
+	for i, id := range cards {
+		if id < 0 || id >= numCards {
+			return nil, fmt.Errorf("trigo: invalid card %d", id)
+		}
+		for _, prev := range cards[:i] {
+			if id == prev {
+				return nil, fmt.Errorf("trigo: repeated card %d", id)
+			}
+		}
+	}
+
+	numAttrs, numVals := c.NumAttrs, c.NumAttrVals
+	attrs := make([][]int, len(cards))
+	for i, id := range cards {
+		attrs[i] = make([]int, numAttrs)
+		for j := range attrs[i] {
+			attrs[i][j] = id % numVals
+			id /= numVals
+		}
+	}
+	place := make([]int, numAttrs)
+	place[0] = 1
+	for j := 1; j < numAttrs; j++ {
+		place[j] = place[j-1] * numVals
+	}
+
+	valPerms := perms(numVals)
+	best := append([]int(nil), cards...)
+	sort.Ints(best)
+	cand := make([]int, len(cards))
+	vp := make([]int, numAttrs) // value permutation of each attribute
+	for _, ap := range perms(numAttrs) {
+		for i := range vp {
+			vp[i] = 0
+		}
+		for {
+			for i, a := range attrs {
+				id := 0
+				for j, v := range a {
+					id += valPerms[vp[j]][v] * place[ap[j]]
+				}
+				cand[i] = id
+			}
+			sort.Ints(cand)
+			if lessInts(cand, best) {
+				copy(best, cand)
+			}
+			j := 0
+			for ; j < numAttrs; j++ {
+				if vp[j]++; vp[j] < len(valPerms) {
+					break
+				}
+				vp[j] = 0
+			}
+			if j == numAttrs {
+				break
+			}
+		}
+	}
+	return best, nil
+}
+
+// CanonicalKey returns a string identifying the canonical form of a
+// collection of cards of a configuration, for use as a map key.
+func CanonicalKey(c Config, cards []int) (string, error) {
+	canon, err := Canonical(c, cards)
+	if err != nil {
+		return "", err
+	}
+	strs := make([]string, len(canon))
+	for i, id := range canon {
+		strs[i] = strconv.Itoa(id)
+	}
+	return strings.Join(strs, ","), nil
+}
+
+// Canonical returns the canonical form of the cards of the puzzle, ignoring
+// blank slots.
+func (p *Puzzle) Canonical() ([]int, error) {
+	cards := []int{}
+	for _, id := range p.Cards() {
+		if id >= 0 {
+			cards = append(cards, id)
+		}
+	}
+	return Canonical(p.Config(), cards)
+}
+
+// perms returns every permutation of 0 to n-1.
+func perms(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	all := [][]int{}
+	for _, p := range perms(n - 1) {
+		for i := 0; i < n; i++ {
+			q := make([]int, 0, n)
+			q = append(q, p[:i]...)
+			q = append(q, n-1)
+			q = append(q, p[i:]...)
+			all = append(all, q)
+		}
+	}
+	return all
+}
+
+// lessInts returns whether a comes before b, comparing values in order.
+func lessInts(a, b []int) bool {
+	for i := range a {
+		if i >= len(b) {
+			return false
+		}
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
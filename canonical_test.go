@@ -0,0 +1,112 @@
+package trigo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// relabel returns the cards with their attributes and the values of each
+// attribute permuted at random, in a random order.
+func relabel(c Config, cards []int, rng *rand.Rand) []int {
+	attrPerm := rng.Perm(c.NumAttrs)
+	valPerms := make([][]int, c.NumAttrs)
+	for j := range valPerms {
+		valPerms[j] = rng.Perm(c.NumAttrVals)
+	}
+	out := make([]int, len(cards))
+	for i, id := range cards {
+		attrs := make([]int, c.NumAttrs)
+		for j := range attrs {
+			attrs[attrPerm[j]] = valPerms[j][id%c.NumAttrVals]
+			id /= c.NumAttrVals
+		}
+		for j := c.NumAttrs - 1; j >= 0; j-- {
+			out[i] = out[i]*c.NumAttrVals + attrs[j]
+		}
+	}
+	rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+func TestCanonicalKey(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, c := range []Config{StdConfig(), {NumAttrs: 3, NumAttrVals: 4, FieldSize: 12, FieldExpand: 4}} {
+		for n := 0; n < 4; n++ {
+			cards := rng.Perm(c.NumCards())[:c.FieldSize]
+			key, err := CanonicalKey(c, cards)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				other := relabel(c, cards, rng)
+				if k, _ := CanonicalKey(c, other); k != key {
+					t.Fatalf("%+v: relabeled %v as %v, key %q, want %q", c, cards, other, k, key)
+				}
+			}
+		}
+	}
+}
+
+func TestCanonicalDistinct(t *testing.T) {
+	c := StdConfig()
+	// a match and three cards that aren't one can't be relabeled as each other
+	match, err := CanonicalKey(c, []int{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notMatch, err := CanonicalKey(c, []int{0, 1, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match == notMatch {
+		t.Errorf("a match and a non-match share the key %q", match)
+	}
+
+	// matches are relabelings of each other exactly when they have the same
+	// number of all-different attributes
+	keys := map[int]string{}
+	tri := newGame(c)
+	rng := rand.New(rand.NewSource(1))
+	for len(keys) < c.NumAttrs || rng.Intn(20) > 0 {
+		tri.setField(rng.Perm(c.NumCards())[:c.FieldSize])
+		m := tri.FindMatch()
+		if m == nil {
+			continue
+		}
+		cards := []int{}
+		for _, f := range m {
+			cards = append(cards, tri.state.Field[f])
+		}
+		key, _ := CanonicalKey(c, cards)
+		diff := tri.allDiff(m)
+		if k, ok := keys[diff]; ok && k != key {
+			t.Fatalf("matches with %d attributes all different have keys %q and %q", diff, k, key)
+		}
+		keys[diff] = key
+	}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if seen[key] {
+			t.Errorf("matches with different all-different attributes share the key %q", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestCanonicalErrors(t *testing.T) {
+	if n := StdConfig().Symmetries(); n != 24*6*6*6*6 {
+		t.Errorf("standard Symmetries() = %d, want %d", n, 24*6*6*6*6)
+	}
+	big := Config{NumAttrs: 6, NumAttrVals: 5, FieldSize: 15, FieldExpand: 5}
+	if n := big.Symmetries(); n != -1 {
+		t.Errorf("Symmetries() of %+v = %d, want -1", big, n)
+	}
+	if _, err := Canonical(big, []int{0, 1, 2}); err != ErrTooSymmetric {
+		t.Errorf("Canonical of %+v: err = %v, want %v", big, err, ErrTooSymmetric)
+	}
+	for _, cards := range [][]int{{0, 81}, {-1}, {3, 4, 3}} {
+		if _, err := Canonical(StdConfig(), cards); err == nil {
+			t.Errorf("Canonical(%v) gave no error", cards)
+		}
+	}
+}
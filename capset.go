@@ -0,0 +1,132 @@
+package trigo
+
+// CapSet describes the largest collection of cards with no match among them,
+// known as a cap set, that a search found for a configuration.
+type CapSet struct {
+	Config Config
+	Cards  []int // IDs of the cards of the largest cap set found
+	Bound  int   // upper bound on the size of any cap set
+	Exact  bool  // whether the search proved Cards as large as possible
+	Nodes  int   // search nodes visited
+}
+
+// MatchField returns the field size that is guaranteed to hold a match, one
+// more than the bound on cap set size.  A field of FieldSize cards needs to
+// expand by at least MatchField()-FieldSize cards for one expansion to always
+// suffice.
+func (c CapSet) MatchField() int {
+	return c.Bound + 1
+}
+
+// capSearch holds the state of a cap set search.
+type capSearch struct {
+	t       *TriGo
+	budget  int
+	nodes   int
+	chosen  []int
+	blocked []int // number of ways each card would complete a match
+	best    []int
+	subset  []int
+}
+
+// FindCapSet searches for the largest cap set of the cards of a configuration.
+// The search stops after budget nodes, unless budget is 0, in which case it
+// finds the largest.  Only the first card is fixed by symmetry, so an exact
+// search is only practical for small decks, such as up to 3 attributes of 3
+// values; the 4-attribute standard deck needs a budget.  The bound comes from the search if it finishes, and
+// otherwise from cap sets of one less attribute, since each of the NumAttrVals
+// slices of cards sharing a value of the last attribute holds at most that
+// many cards of a cap set.
+func FindCapSet(c Config, budget int) (CapSet, error) {
+	if err := c.Validate(); err != nil {
+		return CapSet{}, err
+	}
+	t := newGame(c)
+	s := &capSearch{
+		t:       t,
+		budget:  budget,
+		blocked: make([]int, len(t.state.Cards)),
+		subset:  make([]int, 0, c.NumAttrVals),
+	}
+	// value permutations can take any card to card 0, so start with it
+	s.add(0)
+	s.search(1)
+
+	res := CapSet{Config: c, Cards: s.best, Nodes: s.nodes}
+	res.Exact = budget == 0 || s.nodes < budget
+	switch {
+	case res.Exact:
+		res.Bound = len(s.best)
+	case c.NumAttrs == 1:
+		res.Bound = c.NumAttrVals - 1
+	default:
+		sub := c
+		sub.NumAttrs--
+		sub.FieldSize = c.NumAttrVals
+		subCap, _ := FindCapSet(sub, budget)
+		res.Bound = c.NumAttrVals * subCap.Bound
+	}
+	return res, nil
+}
+
+// add adds card id to the cap set and blocks the cards that would now complete
+// a match.
+func (s *capSearch) add(id int) {
+	s.block(id, 1)
+	s.chosen = append(s.chosen, id)
+	if len(s.chosen) > len(s.best) {
+		s.best = append([]int(nil), s.chosen...)
+	}
+}
+
+// remove undoes the last add.
+func (s *capSearch) remove() {
+	id := s.chosen[len(s.chosen)-1]
+	s.chosen = s.chosen[:len(s.chosen)-1]
+	s.block(id, -1)
+}
+
+// block adjusts by delta the block counts of the cards that complete a match
+// with id and all but one card of some set of chosen cards.
+func (s *capSearch) block(id, delta int) {
+	size := s.t.state.NumAttrVals
+	var recurse func(int)
+	recurse = func(n int) {
+		if len(s.subset) == size-2 {
+			cards := append(s.subset, id)
+			if c, ok := s.t.complete(cards); ok {
+				s.blocked[c] += delta
+			}
+			return
+		}
+		for i := n; i < len(s.chosen); i++ {
+			s.subset = append(s.subset, s.chosen[i])
+			recurse(i + 1)
+			s.subset = s.subset[:len(s.subset)-1]
+		}
+	}
+	recurse(0)
+}
+
+// search tries adding each unblocked card from n on.
+func (s *capSearch) search(n int) {
+	if s.budget > 0 && s.nodes >= s.budget {
+		return
+	}
+	s.nodes++
+	free := 0
+	for c := n; c < len(s.blocked); c++ {
+		if s.blocked[c] == 0 {
+			free++
+		}
+	}
+	for c := n; c < len(s.blocked) && len(s.chosen)+free > len(s.best); c++ {
+		if s.blocked[c] != 0 {
+			continue
+		}
+		free--
+		s.add(c)
+		s.search(c + 1)
+		s.remove()
+	}
+}
@@ -0,0 +1,44 @@
+package trigo
+
+import "testing"
+
+// checkCapSet reports an error if any cards of the set make a match.
+func checkCapSet(t *testing.T, c Config, cards []int) {
+	tri := newGame(c)
+	for i := range cards {
+		for j := i + 1; j < len(cards); j++ {
+			for k := j + 1; k < len(cards); k++ {
+				if bruteIsMatch(tri, []int{cards[i], cards[j], cards[k]}) {
+					t.Errorf("%d attributes: cap set %v holds the match %d, %d, %d",
+						c.NumAttrs, cards, cards[i], cards[j], cards[k])
+				}
+			}
+		}
+	}
+}
+
+func TestFindCapSet(t *testing.T) {
+	for _, tc := range []struct{ attrs, size int }{{1, 2}, {2, 4}, {3, 9}} {
+		c := Config{NumAttrs: tc.attrs, NumAttrVals: 3, FieldSize: 3, FieldExpand: 3}
+		res, err := FindCapSet(c, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Exact || len(res.Cards) != tc.size || res.Bound != tc.size {
+			t.Errorf("%d attributes: found %d cards, bound %d, exact %v, want %d exactly",
+				tc.attrs, len(res.Cards), res.Bound, res.Exact, tc.size)
+		}
+		checkCapSet(t, c, res.Cards)
+	}
+
+	// the standard deck's largest cap set has 20 cards, and the bound from 3
+	// attributes is 27
+	res, err := FindCapSet(StdConfig(), 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Exact || len(res.Cards) > 20 || res.Bound != 27 || res.MatchField() != 28 {
+		t.Errorf("standard deck: found %d cards, bound %d, exact %v", len(res.Cards), res.Bound, res.Exact)
+	}
+	checkCapSet(t, StdConfig(), res.Cards)
+}
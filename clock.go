@@ -0,0 +1,89 @@
+package trigo
+
+import "time"
+
+// SetTimeSource sets the function the game clock reads the current time from.
+// A nil function restores time.Now.  The clock keeps its elapsed time.  A
+// clock that hasn't been read since the game started or was restored hasn't
+// started counting, so it counts only time from the new source.
+func (t *TriGo) SetTimeSource(now func() time.Time) {
+	if !t.counting {
+		t.timeSource = now
+		return
+	}
+	elapsed := t.Elapsed()
+	t.timeSource = now
+	t.state.Elapsed = elapsed
+	t.resumed = t.now()
+}
+
+// now returns the current time from the time source.
+func (t *TriGo) now() time.Time {
+	if t.timeSource == nil {
+		return time.Now()
+	}
+	return t.timeSource()
+}
+
+// startClock resets the game clock and starts it running from when it is
+// first read.
+func (t *TriGo) startClock() {
+	t.state.Elapsed = 0
+	t.state.Paused = false
+	t.state.LastMatchAt = 0
+	t.state.MatchTimes = nil
+	t.counting = false
+}
+
+// Elapsed returns the time the game clock has been running.  A running clock
+// starts counting when it is first read, which is usually when the game is
+// dealt.
+func (t *TriGo) Elapsed() time.Duration {
+	if t.state.Paused {
+		return t.state.Elapsed
+	}
+	if !t.counting {
+		t.counting = true
+		t.resumed = t.now()
+		return t.state.Elapsed
+	}
+	return t.state.Elapsed + t.now().Sub(t.resumed)
+}
+
+// Pause stops the game clock.
+func (t *TriGo) Pause() {
+	if t.state.Paused {
+		return
+	}
+	t.state.Elapsed = t.Elapsed()
+	t.state.Paused = true
+}
+
+// Resume restarts the game clock after Pause.
+func (t *TriGo) Resume() {
+	if !t.state.Paused {
+		return
+	}
+	t.state.Paused = false
+	t.counting = true
+	t.resumed = t.now()
+}
+
+// Paused returns whether the game clock is paused.
+func (t *TriGo) Paused() bool {
+	return t.state.Paused
+}
+
+// MatchTimes returns the time taken to find each match of the current game.
+func (t *TriGo) MatchTimes() []time.Duration {
+	return append([]time.Duration(nil), t.state.MatchTimes...)
+}
+
+// timeMatch records the time taken to find a match, and returns it.
+func (t *TriGo) timeMatch() time.Duration {
+	now := t.Elapsed()
+	d := now - t.state.LastMatchAt
+	t.state.LastMatchAt = now
+	t.state.MatchTimes = append(t.state.MatchTimes, d)
+	return d
+}
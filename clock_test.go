@@ -0,0 +1,93 @@
+package trigo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	clock := &SimClock{}
+	tri := NewStd()
+	tri.SetTimeSource(clock.Now)
+	if d := tri.Elapsed(); d != 0 {
+		t.Fatalf("fresh game: Elapsed() = %v, want 0", d)
+	}
+	check := func(name string, want time.Duration) {
+		if d := tri.Elapsed(); d != want {
+			t.Fatalf("%s: Elapsed() = %v, want %v", name, d, want)
+		}
+	}
+
+	clock.Advance(5 * time.Second)
+	check("running", 5*time.Second)
+	tri.Pause()
+	clock.Advance(10 * time.Second)
+	check("paused", 5*time.Second)
+	if !tri.Paused() {
+		t.Error("Paused() = false after Pause")
+	}
+	tri.Resume()
+	clock.Advance(2 * time.Second)
+	check("resumed", 7*time.Second)
+
+	tri.Shuffle()
+	check("new game", 0)
+	clock.Advance(time.Second)
+	check("new game running", time.Second)
+}
+
+func TestClockSaveRestore(t *testing.T) {
+	for _, paused := range []bool{false, true} {
+		clock := &SimClock{}
+		tri := NewStd()
+		tri.SetTimeSource(clock.Now)
+		tri.Deal()
+		clock.Advance(4 * time.Second)
+		tri.Claim(tri.FindMatch())
+		tri.Deal()
+		clock.Advance(3 * time.Second)
+		if paused {
+			tri.Pause()
+		}
+		state, err := tri.State()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clock.Advance(time.Hour) // time while the game was saved doesn't count
+		restored := NewFromSavedState(state)
+		restored.SetTimeSource(clock.Now)
+		if d := restored.Elapsed(); d != 7*time.Second || restored.Paused() != paused {
+			t.Fatalf("paused %v: restored Elapsed() = %v, Paused() = %v", paused, d, restored.Paused())
+		}
+		restored.Resume()
+		clock.Advance(6 * time.Second)
+		restored.Claim(restored.FindMatch())
+		if d := restored.Elapsed(); d != 13*time.Second {
+			t.Errorf("paused %v: Elapsed() after restore = %v, want 13s", paused, d)
+		}
+		want := []time.Duration{4 * time.Second, 9 * time.Second}
+		if got := restored.MatchTimes(); !reflect.DeepEqual(got, want) {
+			t.Errorf("paused %v: MatchTimes() = %v, want %v", paused, got, want)
+		}
+	}
+}
+
+func TestMatchTimesPaused(t *testing.T) {
+	clock := &SimClock{}
+	tri := clockedGame(clock, 1)
+	clock.Advance(3 * time.Second)
+	tri.Pause()
+	clock.Advance(time.Minute)
+	tri.Resume()
+	clock.Advance(2 * time.Second)
+	tri.Claim(tri.FindMatch())
+	tri.Deal()
+	clock.Advance(time.Second)
+	tri.Claim(tri.FindMatch())
+	want := []time.Duration{5 * time.Second, time.Second}
+	if got := tri.MatchTimes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchTimes() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/ianremmler/trigo"
+)
+
+// runCapSet searches for the largest field without a match, and suggests how
+// much the field must expand for one expansion to always find a match.
+func runCapSet(args []string) {
+	fs := flag.NewFlagSet("capset", flag.ExitOnError)
+	c := configFlags(fs)
+	budget := fs.Int("budget", 1000000, "search nodes to visit before giving up, or 0 for no limit, "+
+		"which only finishes in practice for up to 3 attributes")
+	fs.Parse(args)
+
+	// the field size doesn't matter to the search, so don't let it fail
+	search := *c
+	search.FieldSize = search.NumAttrVals
+	res, err := trigo.FindCapSet(search, *budget)
+	if err != nil {
+		fail(err)
+	}
+	t, _ := trigo.NewFromConfig(search)
+
+	fmt.Printf("largest field without a match found: %d cards", len(res.Cards))
+	if res.Exact {
+		fmt.Printf(" (the largest possible)\n")
+	} else {
+		fmt.Printf(" (search stopped after %d nodes, at most %d possible)\n", res.Nodes, res.Bound)
+	}
+	for _, id := range res.Cards {
+		attrs := ""
+		for _, v := range t.Card(id).Attr {
+			attrs += strconv.Itoa(v)
+		}
+		if c.NumAttrs == len(attrNames) && c.NumAttrVals == len(valNames[0]) {
+			attrs += " " + cardString(t.Card(id))
+		}
+		fmt.Println("  " + attrs)
+	}
+	fmt.Printf("fields of %d cards always hold a match", res.MatchField())
+	if expand := res.MatchField() - c.FieldSize; expand > 0 {
+		fmt.Printf(", so a field of %d needs to expand by %d or more", c.FieldSize, expand)
+	}
+	fmt.Println()
+}
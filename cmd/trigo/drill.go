@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ianremmler/trigo"
+	"github.com/wsxiaoys/terminal"
+	"github.com/wsxiaoys/terminal/color"
+)
+
+// runDrill asks for the card that completes a match, from a few choices.
+func runDrill(args []string) {
+	fs := flag.NewFlagSet("drill", flag.ExitOnError)
+	choices := fs.Int("choices", 6, "number of cards to choose from, at most 9")
+	num := fs.Int("n", 10, "number of questions")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed of the questions")
+	fs.Parse(args)
+
+	if *choices > 9 {
+		*choices = 9
+	}
+	d, err := trigo.NewDrill(trigo.StdConfig(), *choices, *seed)
+	if err != nil {
+		fail(err)
+	}
+
+	terminal.Stdout.Clear()
+	terminal.Stdout.Move(0, 0)
+	fmt.Printf("TriGo drill!  Pick the card that completes the match.\n\n")
+	for i := 0; i < *num; i++ {
+		q := d.Next()
+		given := make([]string, len(q.Cards))
+		for j, id := range q.Cards {
+			given[j] = cardString(d.Card(id))
+		}
+		fmt.Printf("%s  + ?\n\n", strings.Join(given, "  "))
+		for j, id := range q.Choices {
+			fmt.Printf("%d.%s  ", j+1, cardString(d.Card(id)))
+		}
+		fmt.Printf("\n\n[question %d of %d, streak: %d] > ", i+1, *num, d.Stats().Streak)
+		str := ""
+		fmt.Scan(&str)
+
+		terminal.Stdout.Clear()
+		terminal.Stdout.Move(0, 0)
+
+		choice, err := strconv.Atoi(strings.TrimSpace(str))
+		right, took := d.Answer(choice - 1)
+		answer := cardString(d.Card(q.Choices[q.Answer]))
+		switch {
+		case right:
+			color.Printf("@g✔@| %s in %s\n\n", answer, took.Round(100*time.Millisecond))
+		case err != nil || choice < 1 || choice > len(q.Choices):
+			color.Printf("@r✘@| Invalid choice.  The answer was %s.\n\n", answer)
+		default:
+			color.Printf("@r✘@| The answer was %s.\n%s\n\n", answer,
+				explainChecks(d.Explain(choice-1)))
+		}
+	}
+	st := d.Stats()
+	fmt.Printf("You got %d of %d right, in %s on average.  Best streak: %d.\n",
+		st.Correct, st.Answered, st.MeanTime().Round(100*time.Millisecond), st.BestStreak)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/ianremmler/trigo"
+)
+
+func TestRunDrill(t *testing.T) {
+	// the drill asks the questions of a drill with the same seed
+	d, err := trigo.NewDrill(trigo.StdConfig(), 6, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := []string{}
+	for i := 0; i < 3; i++ {
+		q := d.Next()
+		wrong := (q.Answer+1)%len(q.Choices) + 1
+		input = append(input, []string{"x", strconv.Itoa(wrong), strconv.Itoa(q.Answer + 1)}[i])
+	}
+	withInput(t, input, func() { runDrill([]string{"-n", "3", "-seed", "1", "-choices", "6"}) })
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ianremmler/trigo"
+)
+
+// runEndgame plays a seeded game with a bot until few cards remain, then
+// reports whether every card can still be cleared.
+func runEndgame(args []string) {
+	fs := flag.NewFlagSet("endgame", flag.ExitOnError)
+	c := configFlags(fs)
+	seed := fs.Int64("seed", 1, "seed of the game")
+	remain := fs.Int("remain", 24, "number of cards left in the deck when solving")
+	budget := fs.Int("budget", 0, "positions to search before giving up (default no limit)")
+	fs.Parse(args)
+
+	t, err := trigo.NewFromConfig(*c)
+	if err != nil {
+		fail(err)
+	}
+	bot := trigo.NewBot(trigo.PerfectSkill(), *seed)
+	t.Seed(*seed)
+	t.Shuffle()
+	t.Deal()
+	for t.DeckSize() > *remain && !t.Over() {
+		a := bot.Act(t.Clone())
+		switch a.Kind {
+		case trigo.ClaimAction:
+			t.Claim(a.Slots)
+			t.Deal()
+		case trigo.NoMatchAction:
+			t.DeclareNoMatch()
+		}
+	}
+
+	res := t.SolveEndgame(*budget)
+	fmt.Printf("deck: %d, field: %d, positions searched: %d\n",
+		t.DeckSize(), len(t.Field())-blanks(t.Field()), res.Nodes)
+	switch {
+	case res.Clearable && res.Next == nil:
+		fmt.Println("clearable, no match on the field, deal more cards next")
+	case res.Clearable:
+		fmt.Printf("clearable, next match: %v\n", res.Next)
+	case res.Complete:
+		fmt.Println("not clearable")
+	default:
+		fmt.Println("undecided within the budget")
+	}
+}
+
+// blanks returns the number of blank cards in field.
+func blanks(field []trigo.Card) int {
+	n := 0
+	for _, c := range field {
+		if c.Blank {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ianremmler/trigo/store"
+)
+
+// openStore opens the store in the default directory.
+func openStore() (*store.Store, error) {
+	dir, err := store.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(dir)
+}
+
+// runExport writes a player's profile and results.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	name := fs.String("player", "player", "name of the player")
+	format := fs.String("format", "json", "output format: json (profile and results) or csv (results)")
+	fs.Parse(args)
+
+	st, err := openStore()
+	if err != nil {
+		fail(err)
+	}
+	switch *format {
+	case "json":
+		err = st.ExportJSON(os.Stdout, *name)
+	case "csv":
+		err = st.ExportCSV(os.Stdout, *name)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
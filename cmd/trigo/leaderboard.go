@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ianremmler/trigo"
+	"github.com/ianremmler/trigo/store"
+)
+
+// addEntry adds the finished game to the leaderboard of its mode, and reports
+// its place.
+func addEntry(res trigo.Result) {
+	e := store.Entry{
+		Player:   player,
+		Finished: time.Now(),
+		Result:   res,
+		Replay:   tri.Replay(seed),
+	}
+	if err := stats.AddEntry(e); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n", err)
+		return
+	}
+	entries, err := stats.Leaderboard(res.Mode.Kind, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n", err)
+		return
+	}
+	for i, other := range entries {
+		if other.Player == e.Player && other.Finished.Equal(e.Finished) {
+			fmt.Printf("You placed %d of %d on the %s leaderboard.\n\n", i+1, len(entries), res.Mode.Kind)
+			return
+		}
+	}
+}
+
+// runLeaderboard lists the best games of a mode, optionally replaying each to
+// check it.
+func runLeaderboard(args []string) {
+	fs := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	modeName := fs.String("mode", "endless", "game mode: endless, blitz, attack or survival")
+	num := fs.Int("n", 10, "number of games to list, or 0 for all")
+	verify := fs.Bool("verify", false, "replay each game to check its result")
+	fs.Parse(args)
+
+	kind, err := trigo.ParseModeKind(*modeName)
+	if err != nil {
+		fail(err)
+	}
+	st, err := openStore()
+	if err != nil {
+		fail(err)
+	}
+	entries, err := st.Leaderboard(kind, *num)
+	if err != nil {
+		fail(err)
+	}
+	for i, e := range entries {
+		fmt.Printf("%3d. %-16s %s (%s)", i+1, e.Player, e.Result.Headline(),
+			e.Finished.Format("2006-01-02"))
+		if *verify {
+			if err := e.Verify(); err != nil {
+				fmt.Printf("  ✘ %v", err)
+			} else {
+				fmt.Print("  ✔")
+			}
+		}
+		fmt.Println()
+	}
+}
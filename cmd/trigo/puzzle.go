@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ianremmler/trigo"
+	"github.com/wsxiaoys/terminal"
+	"github.com/wsxiaoys/terminal/color"
+)
+
+// runPuzzle generates a puzzle, the daily one by default, and asks for every
+// match in it.
+func runPuzzle(args []string) {
+	fs := flag.NewFlagSet("puzzle", flag.ExitOnError)
+	spec := trigo.StdPuzzleSpec()
+	seed := fs.Int64("seed", trigo.DailySeed(time.Now()), "seed of the puzzle (default today's)")
+	fs.IntVar(&spec.Config.FieldSize, "field", spec.Config.FieldSize, "number of cards")
+	fs.IntVar(&spec.Matches, "matches", spec.Matches, "number of matches")
+	fs.IntVar(&spec.MinAllDiff, "alldiff", spec.MinAllDiff, "least number of matches with every attribute different")
+	fs.BoolVar(&spec.Disjoint, "disjoint", spec.Disjoint, "whether matches may not share cards")
+	fs.IntVar(&spec.Budget, "budget", spec.Budget, "fields to try before giving up (default 200000)")
+	fs.Parse(args)
+
+	// the field is shown in three rows, with a key for each card
+	if n := spec.Config.FieldSize; n < 3 || n%3 != 0 || n > len(keys) {
+		fail(fmt.Errorf("puzzle fields need a multiple of 3 cards, from 3 to %d, not %d", len(keys), n))
+	}
+	p, err := trigo.GeneratePuzzle(spec, *seed)
+	if err != nil {
+		fail(err)
+	}
+	playPuzzle(p)
+}
+
+// playPuzzle runs a puzzle until every match is found.
+func playPuzzle(p *trigo.Puzzle) {
+	terminal.Stdout.Clear()
+	terminal.Stdout.Move(0, 0)
+	fmt.Printf("TriGo puzzle!  Find all %d matches.  (%s)\n\n", p.NumMatches(),
+		p.Difficulty().Level())
+
+	start := time.Now()
+	field := p.Field()
+	for !p.Done() {
+		printField(field)
+		for _, m := range p.Found() {
+			fmt.Printf("\n  %s", candidateString(field, m))
+		}
+		fmt.Printf("\n\n[found: %d of %d] > ", len(p.Found()), p.NumMatches())
+		str := ""
+		fmt.Scan(&str)
+
+		terminal.Stdout.Clear()
+		terminal.Stdout.Move(0, 0)
+
+		candidate, ok := parseCandidate(strings.TrimSpace(str))
+		if !ok {
+			continue
+		}
+		candidateStr := candidateString(field, candidate)
+		switch err := p.Claim(candidate); err {
+		case nil:
+			color.Printf("@g✔@| %s @g✔\n\n", candidateStr)
+		case trigo.ErrAlreadyFound:
+			fmt.Printf("You already found %s.\n\n", candidateStr)
+		default:
+			color.Printf("@r✘@| %s @r✘\n%s\n\n", candidateStr, explainChecks(p.Explain(candidate)))
+		}
+	}
+	printField(field)
+	fmt.Printf("\nYou found all %d matches in %s.\n", p.NumMatches(),
+		time.Since(start).Round(time.Second))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ianremmler/trigo"
+)
+
+// withInput runs fn with stdin reading the given lines, and stdout discarded.
+func withInput(t *testing.T, lines []string, fn func()) {
+	in, err := os.CreateTemp(t.TempDir(), "input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in.WriteString(strings.Join(lines, "\n") + "\n")
+	in.Seek(0, 0)
+	out, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin, stdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = in, out
+	defer func() {
+		os.Stdin, os.Stdout = stdin, stdout
+		in.Close()
+		out.Close()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("still waiting for input")
+	}
+}
+
+func TestPlayPuzzle(t *testing.T) {
+	p, err := trigo.GeneratePuzzle(trigo.StdPuzzleSpec(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// find the matches by trying every set of slots on a copy
+	matches, nonMatch := []string{}, ""
+	q, _ := trigo.NewPuzzle(p.Config(), p.Cards())
+	for i := range p.Cards() {
+		for j := i + 1; j < len(p.Cards()); j++ {
+			for k := j + 1; k < len(p.Cards()); k++ {
+				str := string([]byte{keys[i], keys[j], keys[k]})
+				if q.Claim([]int{i, j, k}) == nil {
+					matches = append(matches, str)
+				} else {
+					nonMatch = str
+				}
+			}
+		}
+	}
+
+	// bad input, a repeated match and a non-match are all survived
+	input := append([]string{"qq", "qqa", nonMatch, matches[0], matches[0]}, matches[1:]...)
+	withInput(t, input, func() { playPuzzle(p) })
+	if !p.Done() {
+		t.Errorf("found %d of %d matches", len(p.Found()), p.NumMatches())
+	}
+}
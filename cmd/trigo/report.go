@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ianremmler/trigo"
+)
+
+// printReport shows what happened after each deal of a game, and how the
+// player did with each type of match.
+func printReport(r trigo.Report) {
+	fmt.Printf("%4s  %7s  %-29s  %5s  %6s  %5s  %7s\n",
+		"deal", "matches", "taken", "time", "missed", "hints", "invalid")
+	for i, d := range r.Deals {
+		taken := strings.Repeat(" ", 29)
+		if d.Taken != nil {
+			cards := make([]string, len(d.Taken))
+			for j, id := range d.Taken {
+				cards[j] = cardString(tri.Card(id))
+			}
+			taken = strings.Join(cards, " ")
+		}
+		fmt.Printf("%4d  %7d  %s  %5s  %6d  %5d  %7d\n", i+1, len(d.Available), taken,
+			d.Time.Round(time.Second), len(d.Missed), d.Hints, d.InvalidClaims)
+	}
+	fmt.Println()
+	for _, p := range r.Patterns {
+		if p.Available == 0 {
+			continue
+		}
+		fmt.Printf("%s: took %d of %d offered (%d%%)", patternName(p), p.Taken, p.Available, 100*p.Taken/p.Available)
+		if p.Taken > 0 {
+			fmt.Printf(", in %s on average", p.MeanTime().Round(time.Second))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// writeReport writes the report as JSON to the named file.
+func writeReport(r trigo.Report, name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := r.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ianremmler/trigo"
+	"github.com/ianremmler/trigo/sim"
+)
+
+var skills = map[string]func() trigo.BotSkill{
+	"perfect": trigo.PerfectSkill,
+	"expert":  trigo.ExpertSkill,
+	"novice":  trigo.NoviceSkill,
+}
+
+// dealPolicies make the deal policies the simulation can use, given how many
+// cards they choose from.  A nil policy deals the deck in order.
+var dealPolicies = map[string]func(lookahead int) trigo.DealPolicy{
+	"order": func(int) trigo.DealPolicy { return nil },
+	"beginner": func(lookahead int) trigo.DealPolicy {
+		return trigo.BeginnerDeal{Lookahead: lookahead}
+	},
+}
+
+// configFlags defines flags for the game parameters on fs, defaulting to a
+// standard game.
+func configFlags(fs *flag.FlagSet) *trigo.Config {
+	c := trigo.StdConfig()
+	fs.IntVar(&c.NumAttrs, "attrs", c.NumAttrs, "number of card attributes")
+	fs.IntVar(&c.NumAttrVals, "vals", c.NumAttrVals, "number of values of each attribute")
+	fs.IntVar(&c.FieldSize, "field", c.FieldSize, "number of cards on the field")
+	fs.IntVar(&c.FieldExpand, "expand", c.FieldExpand, "number of cards added to expand the field")
+	return &c
+}
+
+// fail prints err and exits.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+// runSim runs a Monte Carlo simulation and writes its results.
+func runSim(args []string) {
+	fs := flag.NewFlagSet("sim", flag.ExitOnError)
+	c := configFlags(fs)
+	games := fs.Int("n", 1000, "number of games")
+	seed := fs.Int64("seed", 1, "seed of the first game")
+	workers := fs.Int("workers", 0, "games played at once (default one per CPU)")
+	skill := fs.String("skill", "perfect", "skill of the bot: perfect, expert or novice")
+	deal := fs.String("deal", "order", "deal policy: order, or beginner for the easiest fields")
+	lookahead := fs.Int("lookahead", beginnerLookahead, "number of cards the deal policy chooses from")
+	format := fs.String("format", "json", "output format: json for a summary, or csv for each game")
+	fs.Parse(args)
+
+	skillFunc, ok := skills[*skill]
+	if !ok {
+		fail(fmt.Errorf("unknown skill %q", *skill))
+	}
+	dealFunc, ok := dealPolicies[*deal]
+	if !ok {
+		fail(fmt.Errorf("unknown deal policy %q", *deal))
+	}
+	summary, err := sim.Run(sim.Options{
+		Config:  *c,
+		Games:   *games,
+		Seed:    *seed,
+		Workers: *workers,
+		Skill:   skillFunc(),
+		NewDealPolicy: func() trigo.DealPolicy {
+			return dealFunc(*lookahead)
+		},
+	})
+	if err != nil {
+		fail(err)
+	}
+	switch *format {
+	case "json":
+		err = summary.WriteJSON(os.Stdout)
+	case "csv":
+		err = summary.WriteCSV(os.Stdout)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
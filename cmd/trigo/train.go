@@ -0,0 +1,19 @@
+package main
+
+import "github.com/ianremmler/trigo"
+
+// trainLookahead is the number of cards the training deal chooses from.
+const trainLookahead = 6
+
+// setTraining deals toward the player's current weaknesses.
+func setTraining() {
+	tri.SetDealPolicy(trigo.TrainingDeal{Weights: profile.Weaknesses.Weights(), Lookahead: trainLookahead})
+}
+
+// patternName describes a pattern, such as "fill all different".
+func patternName(p trigo.PatternStats) string {
+	if p.AllDiff {
+		return attrNames[p.Attr] + " all different"
+	}
+	return attrNames[p.Attr] + " all same"
+}
@@ -2,17 +2,30 @@ package main
 
 import (
 	"github.com/ianremmler/trigo"
+	"github.com/ianremmler/trigo/store"
 	"github.com/wsxiaoys/terminal"
 	"github.com/wsxiaoys/terminal/color"
 
+	"flag"
 	"fmt"
 	"math/rand"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const (
-	keys = "qazwsxedcrfvtgbyhn"
+	keys       = "qazwsxedcrfvtgbyhn"
+	hintKey    = "?"
+	noMatchKey = "!"
+	solveKey   = "*"
+
+	// beginnerLookahead is the number of cards the beginner deal chooses from.
+	beginnerLookahead = 6
+
+	// solveBudget limits the positions the endgame solver searches.
+	solveBudget = 100000
 )
 
 var (
@@ -22,83 +35,315 @@ var (
 		{"○", "◑", "●"},
 		{"△", "◮", "▲"},
 	}
+	attrNames = []string{"number", "color", "shape", "fill"}
+	valNames  = [][]string{
+		{"single", "double", "triple"},
+		{"red", "green", "magenta"},
+		{"square", "circle", "triangle"},
+		{"outline", "striped", "solid"},
+	}
+	countNames   = []string{"no", "one", "two", "three"}
 	matchesFound = 0
+	hinted       = map[int]struct{}{}
+	tracker      = false
+	reportFile   = ""
+	player       = ""
+	stats        *store.Store
+	profile      *store.Profile
+	training     = false
+	ranked       = false
+	seed         int64
 	tri          *trigo.TriGo
 )
 
+// commands are run in place of the game when named as the first argument.
+var commands = map[string]func(args []string){
+	"sim":         runSim,
+	"endgame":     runEndgame,
+	"capset":      runCapSet,
+	"puzzle":      runPuzzle,
+	"drill":       runDrill,
+	"tutorial":    runTutorial,
+	"leaderboard": runLeaderboard,
+	"export":      runExport,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
+		}
+	}
+
+	modeName := flag.String("mode", "endless", "game mode: endless, blitz, attack or survival")
+	limit := flag.Duration("limit", 0, "time limit for blitz, or starting time for survival")
+	bonus := flag.Duration("bonus", 0, "time added for each match in survival")
+	flag.BoolVar(&tracker, "tracker", false, "show what the cards seen tell about the deck")
+	flag.StringVar(&reportFile, "report", "", "write the report of each finished game as JSON to this file")
+	flag.StringVar(&player, "player", "player", "name of the player, for keeping results and weaknesses")
+	flag.BoolVar(&training, "train", false, "deal more matches of the kinds you tend to miss")
+	easy := flag.Bool("easy", false, "deal fields with easier matches, for beginners")
+	flag.Parse()
+
+	kind, err := trigo.ParseModeKind(*modeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	mode := trigo.StdModes()[kind]
+	if *limit > 0 {
+		mode.Limit = *limit
+	}
+	if *bonus > 0 {
+		mode.Bonus = *bonus
+	}
+
 	rand.Seed(time.Now().UnixNano())
 	tri = trigo.NewStd()
+	tri.SetScoring(trigo.StdScoring()...)
+	tri.SetMode(mode)
+	if stats, err = openStore(); err == nil {
+		profile, err = stats.Profile(player)
+	}
+	if err != nil {
+		fail(err)
+	}
+	switch {
+	case training:
+		setTraining()
+	case *easy:
+		tri.SetDealPolicy(trigo.BeginnerDeal{Lookahead: beginnerLookahead})
+	default:
+		// only standard games can be replayed for the leaderboard
+		ranked = mode == trigo.StdModes()[kind]
+	}
 	play()
 }
 
-func play() {
+// newGame seeds, shuffles and deals a new game, so it can be replayed.
+func newGame() {
+	hinted = map[int]struct{}{}
+	seed = rand.Int63()
+	tri.Seed(seed)
 	tri.Shuffle()
 	tri.Deal()
+}
+
+func play() {
+	newGame()
 
 	terminal.Stdout.Clear()
 	terminal.Stdout.Move(0, 0)
-	fmt.Println("TriGo!\n")
+	fmt.Printf("TriGo!\n\n")
 
 	for {
-		printField()
-		fmt.Printf("\n[matches: %02d, deck: %02d] > ", matchesFound, tri.DeckSize())
+		printField(tri.Field())
+		if tracker {
+			printTracker()
+		}
+		timeStr := ""
+		if left := tri.TimeLeft(); left > 0 {
+			timeStr = fmt.Sprintf(", time: %s", left.Round(time.Second))
+		}
+		fmt.Printf("\n[matches: %02d, deck: %02d, score: %d%s, hint: %s, no match: %s] > ",
+			matchesFound, tri.DeckSize(), tri.Score(), timeStr, hintKey, noMatchKey)
 		str := ""
 		fmt.Scan(&str)
 
 		terminal.Stdout.Clear()
 		terminal.Stdout.Move(0, 0)
 
-		str = strings.TrimSpace(str)
-		if len(str) != 3 {
-			fmt.Printf("You must enter 3 cards.\n\n")
+		if tri.TimedOut() {
+			gameOver()
 			continue
 		}
-		candidate := make([]int, 3)
-		candidateStr := ""
-		seen := map[int]struct{}{}
-		isValid := true
-		for i := 0; i < len(str); i++ {
-			idx := strings.Index(keys, string(str[i]))
-			if idx < 0 {
-				isValid = false
-				break
+
+		str = strings.TrimSpace(str)
+		if str == hintKey {
+			hint := tri.Hint()
+			for _, f := range hint.Slots {
+				hinted[f] = struct{}{}
 			}
-			if _, ok := seen[idx]; ok {
-				isValid = false
-				break
+			switch {
+			case !hint.Exists:
+				fmt.Printf("There are no matches.\n\n")
+			case len(hint.Slots) == 0:
+				fmt.Printf("There is a match.  Enter %s again for more help.\n\n", hintKey)
+			default:
+				fmt.Printf("Look at the highlighted cards.\n\n")
 			}
-			seen[idx] = struct{}{}
-			candidate[i] = idx
-			candidateStr += printCard(idx)
-			if i < len(candidate)-1 {
-				candidateStr += " "
+			continue
+		}
+		if str == solveKey {
+			solve()
+			continue
+		}
+		if str == noMatchKey {
+			if !tri.DeclareNoMatch() {
+				fmt.Printf("Look again, there is a match.\n\n")
+				continue
 			}
+			hinted = map[int]struct{}{}
+			if tri.Over() {
+				gameOver()
+			} else {
+				fmt.Printf("Right, there are no matches.  Here are more cards.\n\n")
+			}
+			continue
 		}
-		if !isValid {
-			fmt.Printf("Invalid cards.  Try again.\n\n")
+		candidate, ok := parseCandidate(str)
+		if !ok {
 			continue
 		}
-		if tri.IsMatch(candidate) {
-			tri.Remove(candidate)
+		candidateStr := candidateString(tri.Field(), candidate)
+		if tri.Claim(candidate) {
+			hinted = map[int]struct{}{}
 			tri.Deal()
-			if tri.FieldMatches() == 0 {
-				fmt.Println("You found all the matches!  Let's play again.\n")
-				matchesFound = 0
-				tri.Shuffle()
-				tri.Deal()
+			if tri.Over() {
+				gameOver()
 			} else {
 				color.Printf("@g✔@| %s @g✔\n\n", candidateStr)
 				matchesFound++
 			}
 		} else {
-			color.Printf("@r✘@| %s @r✘\n\n", candidateStr)
+			color.Printf("@r✘@| %s @r✘\n%s\n\n", candidateStr, explain(candidate))
+		}
+	}
+}
+
+// solve reports whether all cards can still be cleared, and highlights the
+// match to take next if so.  The solver knows the order of the deck, so it
+// costs as much as hints revealing a whole match, and ranked games can't use it.
+func solve() {
+	if ranked {
+		fmt.Printf("The solver can't be used in ranked games.\n\n")
+		return
+	}
+	h := tri.Hint()
+	for h.Exists && h.Level < tri.MaxHintLevel() {
+		h = tri.Hint()
+	}
+	res := tri.SolveEndgame(solveBudget)
+	switch {
+	case res.Clearable && res.Next == nil:
+		fmt.Printf("Every card can still be cleared.  There's no match, so enter %s for more cards.\n\n", noMatchKey)
+	case res.Clearable:
+		for _, f := range res.Next {
+			hinted[f] = struct{}{}
 		}
+		fmt.Printf("Every card can still be cleared.  Take the highlighted match next.\n\n")
+	case res.Complete:
+		fmt.Printf("Some cards will be left over, whatever you do.\n\n")
+	default:
+		fmt.Printf("Too many cards are left to work it out.\n\n")
 	}
 }
 
-func printCard(i int) string {
-	card := tri.FieldCard(i)
+// gameOver reports the result of the finished game and starts a new one.
+func gameOver() {
+	res := tri.Result()
+	if res.Cleared {
+		fmt.Printf("You found all the matches!  Let's play again.\n\n")
+	} else {
+		fmt.Printf("Time's up!  Let's play again.\n\n")
+	}
+	fmt.Printf("You %s.\n", res.Headline())
+	fmt.Printf("Score: %d  (matches: %d, hints: %d, invalid claims: %d, "+
+		"false no-match: %d, leftover cards: %d, time: %s)\n\n", res.Score,
+		res.Matches, res.Hints, res.InvalidClaims, res.FalseNoMatches, res.Leftover,
+		res.Elapsed.Round(time.Second))
+	report := tri.Report()
+	printReport(report)
+	if reportFile != "" {
+		if err := writeReport(report, reportFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n\n", err)
+		}
+	}
+	profile.Weaknesses.Add(report)
+	isBest, err := stats.AddResult(profile, res, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n", err)
+	}
+	if isBest && profile.Games > 1 {
+		fmt.Printf("That's your best %s game yet!\n", res.Mode.Kind)
+	}
+	fmt.Printf("Games played: %d, days in a row: %d (longest %d)\n\n",
+		profile.Games, profile.Streak.Current, profile.Streak.Longest)
+	if training {
+		setTraining()
+	} else if p, ok := profile.Weaknesses.Weakest(); ok {
+		fmt.Printf("Matches with %s give you the most trouble.  Try -train to practice them.\n\n",
+			patternName(p))
+	}
+	if ranked {
+		addEntry(res)
+	}
+	matchesFound = 0
+	newGame()
+}
+
+// explain describes the attributes that keep candidate from being a match.
+func explain(candidate []int) string {
+	return explainChecks(tri.Explain(candidate))
+}
+
+// explainChecks describes the failed checks of an explanation.
+func explainChecks(checks []trigo.AttrCheck, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	lines := []string{}
+	for _, check := range checks {
+		if check.Pass() {
+			continue
+		}
+		vals := []string{}
+		for val, n := range check.Counts(len(valNames[check.Attr])) {
+			if n > 0 {
+				vals = append(vals, countNames[n]+" "+valNames[check.Attr][val])
+			}
+		}
+		lines = append(lines, attrNames[check.Attr]+": "+strings.Join(vals, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseCandidate returns the field slots named by str, reporting why if they
+// can't be a candidate.
+func parseCandidate(str string) ([]int, bool) {
+	if len(str) != 3 {
+		fmt.Printf("You must enter 3 cards.\n\n")
+		return nil, false
+	}
+	candidate := make([]int, 3)
+	seen := map[int]struct{}{}
+	for i := 0; i < len(str); i++ {
+		idx := strings.Index(keys, string(str[i]))
+		if _, ok := seen[idx]; idx < 0 || ok {
+			fmt.Printf("Invalid cards.  Try again.\n\n")
+			return nil, false
+		}
+		seen[idx] = struct{}{}
+		candidate[i] = idx
+	}
+	return candidate, true
+}
+
+// candidateString shows the cards in the given field slots.
+func candidateString(field []trigo.Card, candidate []int) string {
+	strs := make([]string, len(candidate))
+	for i, f := range candidate {
+		card := trigo.Card{Blank: true}
+		if f < len(field) {
+			card = field[f]
+		}
+		strs[i] = cardString(card)
+	}
+	return strings.Join(strs, " ")
+}
+
+func cardString(card trigo.Card) string {
 	str := ""
 	if card.Blank {
 		str = "[       ]"
@@ -112,8 +357,31 @@ func printCard(i int) string {
 	return color.Sprint(str)
 }
 
-func printField() {
-	field := tri.Field()
+// printTracker shows what the sum invariant tells about the deck and the cards
+// that will be left over.
+func printTracker() {
+	l, ok := tri.PredictLeftover()
+	if !ok {
+		return
+	}
+	fmt.Println()
+	if tri.DeckSize() > 0 {
+		fmt.Printf("deck sum: %s", cardString(trigo.Card{Attr: l.DeckSum}))
+		if l.DeckCard >= 0 {
+			fmt.Print(" (the last card)")
+		}
+		fmt.Print("  ")
+	}
+	counts := []string{}
+	for n := 0; n <= l.Remaining && len(counts) < 4; n++ {
+		if l.Possible(n) {
+			counts = append(counts, strconv.Itoa(n))
+		}
+	}
+	fmt.Printf("leftover cards: %s, ...\n", strings.Join(counts, ", "))
+}
+
+func printField(field []trigo.Card) {
 	for i := range field {
 		numCards := len(field)
 		numCols := numCards / 3
@@ -122,7 +390,10 @@ func printField() {
 		if f >= 0 && f < len(keys) {
 			tag = string(keys[f])
 		}
-		fmt.Printf("%s.%s", tag, printCard(f))
+		if _, ok := hinted[f]; ok {
+			tag = color.Sprint("@y" + tag + "@|")
+		}
+		fmt.Printf("%s.%s", tag, cardString(field[f]))
 		if (i+1)%numCols == 0 {
 			fmt.Println()
 		} else {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ianremmler/trigo"
+	"github.com/wsxiaoys/terminal"
+	"github.com/wsxiaoys/terminal/color"
+)
+
+// runTutorial teaches the rules, one attribute at a time.
+func runTutorial(args []string) {
+	terminal.Stdout.Clear()
+	terminal.Stdout.Move(0, 0)
+	lessons := trigo.StdTutorial()
+	for i, l := range lessons {
+		p, err := l.Puzzle()
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("Lesson %d of %d: %s\n\n%s\n\n", i+1, len(lessons), l.Title, l.Text)
+		field := p.Field()
+		for !p.Done() {
+			printField(field)
+			fmt.Printf("\n[found: %d of %d] > ", len(p.Found()), p.NumMatches())
+			str := ""
+			fmt.Scan(&str)
+			fmt.Println()
+
+			candidate, ok := parseCandidate(strings.TrimSpace(str))
+			if !ok {
+				continue
+			}
+			candidateStr := candidateString(field, candidate)
+			switch err := p.Claim(candidate); err {
+			case nil:
+				checks, _ := p.Explain(candidate)
+				color.Printf("@g✔@| %s @g✔\n%s\n\n", candidateStr, describeChecks(checks))
+			case trigo.ErrAlreadyFound:
+				fmt.Printf("You already found %s.\n\n", candidateStr)
+			default:
+				color.Printf("@r✘@| %s @r✘\n%s\n\n", candidateStr, explainChecks(p.Explain(candidate)))
+			}
+		}
+		fmt.Printf("Well done!\n\n")
+	}
+	fmt.Printf("You know the rules.  Have fun playing TriGo!\n")
+}
+
+// describeChecks tells whether each attribute is all the same or all
+// different.
+func describeChecks(checks []trigo.AttrCheck) string {
+	lines := []string{}
+	for _, check := range checks {
+		switch {
+		case check.Same:
+			lines = append(lines, attrNames[check.Attr]+": all the same")
+		case check.Diff:
+			lines = append(lines, attrNames[check.Attr]+": all different")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
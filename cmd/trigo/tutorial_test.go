@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ianremmler/trigo"
+)
+
+func TestRunTutorial(t *testing.T) {
+	// find every match of each lesson, after a non-match where there is one
+	input := []string{}
+	for _, l := range trigo.StdTutorial() {
+		p, err := l.Puzzle()
+		if err != nil {
+			t.Fatal(err)
+		}
+		matches, nonMatches := []string{}, []string{}
+		n := len(p.Cards())
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				for k := j + 1; k < n; k++ {
+					str := string([]byte{keys[i], keys[j], keys[k]})
+					if p.Claim([]int{i, j, k}) == nil {
+						matches = append(matches, str)
+					} else {
+						nonMatches = append(nonMatches, str)
+					}
+				}
+			}
+		}
+		if len(nonMatches) > 0 {
+			input = append(input, nonMatches[0])
+		}
+		input = append(input, matches...)
+	}
+	withInput(t, input, func() { runTutorial(nil) })
+}
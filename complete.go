@@ -0,0 +1,138 @@
+package trigo
+
+import "math/bits"
+
+// Location describes where a card is in a game.
+type Location int
+
+const (
+	Unknown Location = iota
+	InDeck
+	InField
+	Discarded
+)
+
+var locationNames = []string{"unknown", "deck", "field", "discarded"}
+
+func (l Location) String() string {
+	if l < 0 || int(l) >= len(locationNames) {
+		return locationNames[Unknown]
+	}
+	return locationNames[l]
+}
+
+// NumCards returns the number of cards in the game.
+func (t *TriGo) NumCards() int {
+	return len(t.state.Cards)
+}
+
+// FieldCardID returns the ID of the ith field card, or -1 if the slot is empty
+// or i is out of range.
+func (t *TriGo) FieldCardID(i int) int {
+	if i < 0 || i >= len(t.state.Field) {
+		return -1
+	}
+	return t.state.Field[i]
+}
+
+// Location returns where the card with the given ID currently is.
+func (t *TriGo) Location(id int) Location {
+	if id < 0 || id >= len(t.state.Cards) {
+		return Unknown
+	}
+	if t.isInField(id) {
+		return InField
+	}
+	for _, c := range t.state.Deck {
+		if c == id {
+			return InDeck
+		}
+	}
+	return Discarded
+}
+
+// validCards returns whether cards are distinct, valid card IDs.
+func (t *TriGo) validCards(cards []int) bool {
+	for i, c := range cards {
+		if c < 0 || c >= len(t.state.Cards) {
+			return false
+		}
+		for _, d := range cards[:i] {
+			if c == d {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// partialMatch returns whether cards could all be part of the same match.
+func (t *TriGo) partialMatch(cards []int) bool {
+	numVals := uint(t.state.NumAttrVals)
+	full := uint64(1)<<numVals - 1
+	for w := 0; w < t.words; w++ {
+		m := uint64(0)
+		for _, c := range cards {
+			m |= t.attrBits[c*t.words+w]
+		}
+		for i := 0; i < t.attrsInWord(w); i++ {
+			n := bits.OnesCount64(m >> (uint(i) * numVals) & full)
+			if n != 1 && n != len(cards) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CompleteMatch returns the ID of the card that completes a match with the
+// given cards, which must number one less than the match size.  ok is false if
+// the cards are not distinct and valid, or can't be part of a match.  Use
+// Location to find where the completing card is.
+func (t *TriGo) CompleteMatch(cards []int) (id int, ok bool) {
+	if len(cards) != t.state.NumAttrVals-1 || len(cards) < 2 || !t.validCards(cards) {
+		return -1, false
+	}
+	return t.complete(cards)
+}
+
+// CompleteMatches returns every set of cards that completes a match with the
+// given cards, which may be any number fewer than the match size.  Each set is
+// in ascending order of card ID.
+func (t *TriGo) CompleteMatches(cards []int) [][]int {
+	size := t.state.NumAttrVals
+	if len(cards) >= size || !t.validCards(cards) || !t.partialMatch(cards) {
+		return nil
+	}
+	if len(cards) == size-1 && size >= 3 {
+		if id, ok := t.complete(cards); ok {
+			return [][]int{{id}}
+		}
+		return nil
+	}
+
+	sets := [][]int{}
+	given := len(cards)
+	cand := append(make([]int, 0, size), cards...)
+	var recurse func(int)
+	recurse = func(n int) {
+		for c := n; c < len(t.state.Cards); c++ {
+			cand = append(cand, c)
+			switch {
+			case !t.validCards(cand) || !t.partialMatch(cand):
+			case len(cand) < size-1 || (len(cand) < size && size < 3):
+				recurse(c + 1)
+			case len(cand) == size:
+				sets = append(sets, append([]int(nil), cand[given:]...))
+			default:
+				if id, ok := t.complete(cand); ok && id > c && t.validCards(append(cand, id)) {
+					set := append([]int(nil), cand[given:]...)
+					sets = append(sets, append(set, id))
+				}
+			}
+			cand = cand[:len(cand)-1]
+		}
+	}
+	recurse(0)
+	return sets
+}
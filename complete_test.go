@@ -0,0 +1,99 @@
+package trigo
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// bruteCompletions returns every ascending set of cards that completes a match
+// with the given cards, by checking every combination.
+func bruteCompletions(t *TriGo, cards []int) [][]int {
+	sets := [][]int{}
+	need := t.state.NumAttrVals - len(cards)
+	set := []int{}
+	var recurse func(int)
+	recurse = func(n int) {
+		if len(set) == need {
+			if bruteIsMatch(t, append(append([]int(nil), cards...), set...)) {
+				sets = append(sets, append([]int(nil), set...))
+			}
+			return
+		}
+		for c := n; c < len(t.state.Cards); c++ {
+			if t.validCards(append(append([]int(nil), cards...), c)) {
+				set = append(set, c)
+				recurse(c + 1)
+				set = set[:len(set)-1]
+			}
+		}
+	}
+	recurse(0)
+	return sets
+}
+
+func TestCompleteMatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, c := range []Config{StdConfig(), {NumAttrs: 3, NumAttrVals: 4, FieldSize: 12, FieldExpand: 4}} {
+		tri, _ := NewFromConfig(c)
+		size := c.NumAttrVals
+		for n := 0; n < 100; n++ {
+			cards := rng.Perm(tri.NumCards())[:size-1]
+			want := bruteCompletions(tri, cards)
+			id, ok := tri.CompleteMatch(cards)
+			if ok != (len(want) == 1) || ok && id != want[0][0] {
+				t.Fatalf("%+v: CompleteMatch(%v) = %d, %v, want %v", c, cards, id, ok, want)
+			}
+		}
+		if _, ok := tri.CompleteMatch([]int{0, 0, 1}[:size-1]); ok {
+			t.Errorf("%+v: completed repeated cards", c)
+		}
+
+		for given := 0; given < size; given++ {
+			for n := 0; n < 5; n++ {
+				cards := rng.Perm(tri.NumCards())[:given]
+				want := bruteCompletions(tri, cards)
+				got := tri.CompleteMatches(cards)
+				if len(want) == 0 && len(got) == 0 {
+					continue
+				}
+				sortSlots(got)
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("%+v: CompleteMatches(%v) = %v, want %v", c, cards, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestLocation(t *testing.T) {
+	tri := NewStd()
+	tri.Seed(1)
+	tri.Shuffle()
+	tri.Deal()
+	for i := 0; i < 5; i++ {
+		tri.Claim(tri.FindMatch())
+		tri.Deal()
+	}
+	for id := 0; id < tri.NumCards(); id++ {
+		want := Discarded
+		for _, c := range tri.state.Field {
+			if c == id {
+				want = InField
+			}
+		}
+		for _, c := range tri.state.Deck {
+			if c == id {
+				want = InDeck
+			}
+		}
+		if got := tri.Location(id); got != want {
+			t.Errorf("Location(%d) = %v, want %v", id, got, want)
+		}
+	}
+	for _, id := range []int{-1, tri.NumCards()} {
+		if got := tri.Location(id); got != Unknown {
+			t.Errorf("Location(%d) = %v, want %v", id, got, Unknown)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package trigo
+
+import "fmt"
+
+// maxCards limits the size of the card table a configuration may generate.
+const maxCards = 1 << 20
+
+// Config holds the parameters of a game.
+type Config struct {
+	NumAttrs    int // number of attributes of each card
+	NumAttrVals int // number of values of each attribute, and the match size
+	FieldSize   int // number of cards normally on the field
+	FieldExpand int // number of cards added when the field has no match
+}
+
+// StdConfig returns the configuration of a standard game.
+func StdConfig() Config {
+	return Config{NumAttrs: 4, NumAttrVals: 3, FieldSize: 12, FieldExpand: 3}
+}
+
+// NumCards returns the number of cards in a deck, or -1 if it would exceed the
+// supported maximum.
+func (c Config) NumCards() int {
+	numCards := 1
+	for i := 0; i < c.NumAttrs; i++ {
+		if c.NumAttrVals > 0 && numCards > maxCards/c.NumAttrVals {
+			return -1
+		}
+		numCards *= c.NumAttrVals
+	}
+	return numCards
+}
+
+// Validate returns an error describing why the configuration can't make a
+// playable game, or nil if it can.
+func (c Config) Validate() error {
+	switch {
+	case c.NumAttrs < 1:
+		return fmt.Errorf("trigo: cards need at least 1 attribute, not %d", c.NumAttrs)
+	case c.NumAttrVals < 3:
+		return fmt.Errorf("trigo: attributes need at least 3 values, not %d, "+
+			"or any cards would match", c.NumAttrVals)
+	case c.NumAttrVals > 64:
+		return fmt.Errorf("trigo: attributes can have at most 64 values, not %d", c.NumAttrVals)
+	case c.NumCards() < 0:
+		return fmt.Errorf("trigo: %d attributes with %d values make more than %d cards",
+			c.NumAttrs, c.NumAttrVals, maxCards)
+	case c.FieldSize < c.NumAttrVals:
+		return fmt.Errorf("trigo: field size %d is smaller than the match size %d",
+			c.FieldSize, c.NumAttrVals)
+	case c.FieldSize > c.NumCards():
+		return fmt.Errorf("trigo: field size %d is larger than the deck of %d cards",
+			c.FieldSize, c.NumCards())
+	case c.FieldExpand < 1:
+		return fmt.Errorf("trigo: field expansion must be at least 1, not %d", c.FieldExpand)
+	}
+	return nil
+}
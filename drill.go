@@ -0,0 +1,198 @@
+package trigo
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Drill is a training drill: shown all but one card of a match, the player
+// picks the card that completes it from a few choices.  The other choices
+// differ from the answer in as few attributes as possible.
+type Drill struct {
+	t          *TriGo
+	rng        *rand.Rand
+	numChoices int
+	question   DrillQuestion
+	asked      time.Time
+	stats      DrillStats
+}
+
+// DrillQuestion is a question of a drill.  Cards are given by ID.
+type DrillQuestion struct {
+	Cards   []int // all but one card of a match
+	Choices []int // cards to choose from
+	Answer  int   // index in Choices of the card that completes the match
+}
+
+// DrillStats summarizes the answers given in a drill.
+type DrillStats struct {
+	Answered   int
+	Correct    int
+	Streak     int // correct answers in a row, up to the last
+	BestStreak int
+	Time       time.Duration // total time taken to answer
+}
+
+// MeanTime returns the mean time taken to answer.
+func (s DrillStats) MeanTime() time.Duration {
+	if s.Answered == 0 {
+		return 0
+	}
+	return s.Time / time.Duration(s.Answered)
+}
+
+// NewDrill returns a drill for cards of the given configuration, offering the
+// given number of choices, whose questions are determined by seed.
+func NewDrill(c Config, choices int, seed int64) (*Drill, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.NumAttrVals < 3 {
+		return nil, errors.New("trigo: drill needs at least 3 attribute values")
+	}
+	if choices < 1 || choices > c.NumCards()-c.NumAttrVals+1 {
+		return nil, errors.New("trigo: invalid number of drill choices")
+	}
+	return &Drill{
+		t:          newGame(c),
+		rng:        rand.New(rand.NewSource(seed)),
+		numChoices: choices,
+	}, nil
+}
+
+// SetTimeSource sets the function the drill reads the current time from.  A
+// nil function restores time.Now.
+func (d *Drill) SetTimeSource(now func() time.Time) {
+	d.t.timeSource = now
+}
+
+// Card returns the card with the given ID.
+func (d *Drill) Card(id int) Card {
+	return d.t.Card(id)
+}
+
+// Next asks a new question, and starts timing the answer.
+func (d *Drill) Next() DrillQuestion {
+	match := d.randomMatch()
+	hide := d.rng.Intn(len(match))
+	cards := append(append([]int(nil), match[:hide]...), match[hide+1:]...)
+	answer, _ := d.t.CompleteMatch(cards)
+	choices := append(d.distractors(answer, append([]int{answer}, cards...)), answer)
+	d.rng.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+	q := DrillQuestion{Cards: append([]int(nil), cards...), Choices: choices}
+	for i, c := range choices {
+		if c == answer {
+			q.Answer = i
+		}
+	}
+	d.question = q
+	d.asked = d.t.now()
+	return q
+}
+
+// randomMatch returns the card IDs of a random match, with each attribute
+// equally likely to be all the same or all different.
+func (d *Drill) randomMatch() []int {
+	size, numVals := d.t.state.NumAttrVals, d.t.state.NumAttrVals
+	for {
+		match := make([]int, size)
+		place, anyDiff := 1, false
+		for a := 0; a < d.t.state.NumAttrs; a++ {
+			vals := d.rng.Perm(numVals)
+			same := d.rng.Intn(2) == 0
+			for i := range match {
+				v := vals[i]
+				if same {
+					v = vals[0]
+				}
+				match[i] += v * place
+			}
+			place *= numVals
+			anyDiff = anyDiff || !same
+		}
+		if anyDiff {
+			return match
+		}
+	}
+}
+
+// distractors returns cards other than those excluded that differ from the
+// answer in as few attributes as possible, one fewer than the number of
+// choices.
+func (d *Drill) distractors(answer int, exclude []int) []int {
+	byDist := make([][]int, d.t.state.NumAttrs+1)
+	attrs := d.t.state.Cards[answer].Attr
+next:
+	for _, c := range d.rng.Perm(len(d.t.state.Cards)) {
+		for _, e := range exclude {
+			if c == e {
+				continue next
+			}
+		}
+		dist := 0
+		for a, v := range d.t.state.Cards[c].Attr {
+			if v != attrs[a] {
+				dist++
+			}
+		}
+		byDist[dist] = append(byDist[dist], c)
+	}
+	picks := []int{}
+	for _, cards := range byDist {
+		for _, c := range cards {
+			if len(picks) == d.numChoices-1 {
+				return picks
+			}
+			picks = append(picks, c)
+		}
+	}
+	return picks
+}
+
+// Question returns the current question.
+func (d *Drill) Question() DrillQuestion {
+	return d.question
+}
+
+// Answer answers the current question with the index of a choice.  It returns
+// whether the answer is right and how long it took.
+func (d *Drill) Answer(choice int) (bool, time.Duration) {
+	took := d.t.now().Sub(d.asked)
+	right := choice == d.question.Answer
+	d.stats.Answered++
+	d.stats.Time += took
+	if right {
+		d.stats.Correct++
+		d.stats.Streak++
+		if d.stats.Streak > d.stats.BestStreak {
+			d.stats.BestStreak = d.stats.Streak
+		}
+	} else {
+		d.stats.Streak = 0
+	}
+	return right, took
+}
+
+// Explain explains the candidate made of the cards of the current question and
+// the given choice, like TriGo.Explain.
+func (d *Drill) Explain(choice int) ([]AttrCheck, error) {
+	q := d.question
+	if choice < 0 || choice >= len(q.Choices) {
+		return nil, ErrInvalidSlot
+	}
+	cards := append(append([]int(nil), q.Cards...), q.Choices[choice])
+	d.t.setField(cards)
+	slots := make([]int, len(cards))
+	for i := range slots {
+		slots[i] = i
+	}
+	return d.t.Explain(slots)
+}
+
+// Stats returns the statistics of the answers given.
+func (d *Drill) Stats() DrillStats {
+	return d.stats
+}
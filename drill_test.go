@@ -0,0 +1,113 @@
+package trigo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDrill(t *testing.T) {
+	for _, choices := range []int{0, 80} {
+		if _, err := NewDrill(StdConfig(), choices, 1); err == nil {
+			t.Errorf("%d choices accepted", choices)
+		}
+	}
+	if _, err := NewDrill(StdConfig(), 79, 1); err != nil {
+		t.Errorf("79 choices: %v", err)
+	}
+}
+
+func TestDrill(t *testing.T) {
+	clock := &SimClock{}
+	d, err := NewDrill(StdConfig(), 6, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetTimeSource(clock.Now)
+	want := DrillStats{}
+	questions := []DrillQuestion{}
+	for i := 0; i < 30; i++ {
+		q := d.Next()
+		questions = append(questions, q)
+		if !reflect.DeepEqual(d.Question(), q) {
+			t.Fatalf("question %d: Question() = %+v, want %+v", i, d.Question(), q)
+		}
+		if len(q.Cards) != 2 || len(q.Choices) != 6 {
+			t.Fatalf("question %d: %d cards and %d choices, want 2 and 6", i, len(q.Cards), len(q.Choices))
+		}
+		answer := q.Choices[q.Answer]
+		if id, ok := d.t.CompleteMatch(q.Cards); !ok || id != answer {
+			t.Errorf("question %d: answer %d, want %d", i, answer, id)
+		}
+		seen := map[int]bool{q.Cards[0]: true, q.Cards[1]: true}
+		for _, c := range q.Choices {
+			if seen[c] {
+				t.Errorf("question %d: card %d repeated in %+v", i, c, q)
+			}
+			seen[c] = true
+			// the nearest distractors differ from the answer in one or two
+			// attributes
+			dist := 0
+			for a, v := range d.Card(c).Attr {
+				if v != d.Card(answer).Attr[a] {
+					dist++
+				}
+			}
+			if c != answer && (dist < 1 || dist > 2) {
+				t.Errorf("question %d: distractor %d differs in %d attributes", i, c, dist)
+			}
+		}
+
+		// get every third answer wrong
+		took := time.Duration(i+1) * time.Second
+		clock.Advance(took)
+		choice := q.Answer
+		if i%3 == 2 {
+			choice = (q.Answer + 1) % len(q.Choices)
+		}
+		right, gotTook := d.Answer(choice)
+		if right != (choice == q.Answer) || gotTook != took {
+			t.Errorf("question %d: answer right %v in %v, want %v in %v", i, right, gotTook, choice == q.Answer, took)
+		}
+		want.Answered++
+		want.Time += took
+		if right {
+			want.Correct++
+			want.Streak++
+		} else {
+			want.Streak = 0
+		}
+		if want.Streak > want.BestStreak {
+			want.BestStreak = want.Streak
+		}
+		if got := d.Stats(); got != want {
+			t.Fatalf("question %d: stats %+v, want %+v", i, got, want)
+		}
+
+		checks, err := d.Explain(choice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok := true
+		for _, c := range checks {
+			ok = ok && (c.Same || c.Diff)
+		}
+		if ok != right {
+			t.Errorf("question %d: choice %d explained as a match %v, want %v", i, choice, ok, right)
+		}
+	}
+	if got, want := d.Stats().MeanTime(), 15500*time.Millisecond; got != want {
+		t.Errorf("mean time %v, want %v", got, want)
+	}
+	if _, err := d.Explain(6); err != ErrInvalidSlot {
+		t.Errorf("Explain(6) = %v, want %v", err, ErrInvalidSlot)
+	}
+
+	// the same seed asks the same questions
+	e, _ := NewDrill(StdConfig(), 6, 1)
+	for i, q := range questions {
+		if got := e.Next(); !reflect.DeepEqual(got, q) {
+			t.Fatalf("question %d: %+v, want %+v", i, got, q)
+		}
+	}
+}
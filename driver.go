@@ -0,0 +1,92 @@
+package trigo
+
+import "time"
+
+// defaultMaxTurns limits driven games whose players never finish.
+const defaultMaxTurns = 10000
+
+// SimClock is a simulated time source, which only moves when advanced.
+type SimClock struct {
+	now time.Time
+}
+
+// Now returns the simulated time.
+func (c *SimClock) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the simulated time forward by d.
+func (c *SimClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Turn records one action taken in a driven game.
+type Turn struct {
+	Player int
+	Action Action
+	At     time.Duration // game clock time of the action
+	OK     bool          // whether a claim or declaration was right
+}
+
+// Record is the outcome of a driven game.
+type Record struct {
+	Result  Result
+	Turns   []Turn
+	Matches []int   // number of matches found by each player
+	Log     []Event // the game log
+}
+
+// Driver plays a game to completion with one or more players, on a simulated
+// clock, so no real time passes.
+type Driver struct {
+	Game     *TriGo
+	Players  []Player
+	Clock    *SimClock // created if nil
+	MaxTurns int       // turns played before giving up, or 0 for a default
+}
+
+// Run plays the game from its current state until it is over.  The game should
+// already be shuffled and dealt, after setting its time source to the clock if
+// no real time at all should count.  On each turn, every player is asked for an
+// action, and the one that takes the least time acts.
+func (d *Driver) Run() Record {
+	if d.Clock == nil {
+		d.Clock = &SimClock{}
+	}
+	maxTurns := d.MaxTurns
+	if maxTurns <= 0 {
+		maxTurns = defaultMaxTurns
+	}
+	g := d.Game
+	g.SetTimeSource(d.Clock.Now)
+
+	rec := Record{Matches: make([]int, len(d.Players))}
+	for len(rec.Turns) < maxTurns && len(d.Players) > 0 && !g.Over() {
+		first, action := -1, Action{}
+		for i, p := range d.Players {
+			a := p.Act(g.Clone())
+			if first < 0 || a.Delay < action.Delay {
+				first, action = i, a
+			}
+		}
+		d.Clock.Advance(action.Delay)
+
+		turn := Turn{Player: first, Action: action, At: g.Elapsed()}
+		switch action.Kind {
+		case ClaimAction:
+			if turn.OK = g.Claim(action.Slots); turn.OK {
+				rec.Matches[first]++
+				g.Deal()
+			}
+		case NoMatchAction:
+			turn.OK = g.DeclareNoMatch()
+		case HintAction:
+			g.Hint()
+			turn.OK = true
+		}
+		rec.Turns = append(rec.Turns, turn)
+	}
+	rec.Result = g.Result()
+	rec.Log = g.Log()
+	return rec
+}
@@ -0,0 +1,47 @@
+package trigo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// drive plays a seeded blitz game with bots of the given skills.
+func drive(seed int64, skills ...BotSkill) Record {
+	clock := &SimClock{}
+	t := clockedGame(clock, seed)
+	t.SetMode(StdModes()[Blitz])
+	d := Driver{Game: t, Clock: clock}
+	for i, s := range skills {
+		d.Players = append(d.Players, NewBot(s, seed+int64(i)))
+	}
+	return d.Run()
+}
+
+func TestDriverReproducible(t *testing.T) {
+	a := drive(1, NoviceSkill(), ExpertSkill())
+	b := drive(1, NoviceSkill(), ExpertSkill())
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the same seeds gave different games")
+	}
+	if len(a.Turns) == 0 || a.Result.Mode != StdModes()[Blitz] {
+		t.Errorf("record of %d turns, result %+v", len(a.Turns), a.Result)
+	}
+	if a.Matches[1] <= a.Matches[0] {
+		t.Errorf("expert found %d matches against the novice's %d", a.Matches[1], a.Matches[0])
+	}
+}
+
+func TestBotSkills(t *testing.T) {
+	skills := []BotSkill{NoviceSkill(), ExpertSkill(), PerfectSkill()}
+	scores := make([]int, len(skills))
+	for i, s := range skills {
+		for seed := int64(1); seed <= 5; seed++ {
+			scores[i] += drive(seed, s).Result.Score
+		}
+	}
+	for i := 1; i < len(scores); i++ {
+		if scores[i] < scores[i-1] {
+			t.Errorf("stronger bot %d scored %d, below %d", i, scores[i], scores[i-1])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package trigo
+
+import "encoding/binary"
+
+// Endgame is the outcome of an endgame search.
+type Endgame struct {
+	Clearable bool  // whether every card can still be removed
+	Next      []int // field slots of a match to take next toward a clear
+	Nodes     int   // positions searched
+	Complete  bool  // whether the search finished within its budget
+}
+
+// endgameSolver holds the state of an endgame search.
+type endgameSolver struct {
+	budget int
+	nodes  int
+	failed map[string]bool // positions known not to clear
+}
+
+// SolveEndgame searches for a sequence of matches that removes every card,
+// starting from the current position and dealing from the deck in its known
+// order.  The search gives up after budget positions, unless budget is 0.
+// Clearable is only false for certain if Complete is true.  Next is nil when
+// the field has no match, as more cards must be dealt first.  The result
+// assumes the game's deal policy, if any, deals the same way every time.
+func (t *TriGo) SolveEndgame(budget int) Endgame {
+	s := &endgameSolver{budget: budget, failed: map[string]bool{}}
+	res := Endgame{}
+	res.Clearable, res.Next = s.solve(t.searchClone())
+	res.Nodes = s.nodes
+	res.Complete = res.Clearable || budget == 0 || s.nodes < budget
+	return res
+}
+
+// searchClone returns a copy of the game for searching, without the log or
+// scoring.
+func (t *TriGo) searchClone() *TriGo {
+	c := t.Clone()
+	c.policies = nil
+	c.state.Log = nil
+	return c
+}
+
+// positionKey identifies a position by the cards on the field and the number
+// left in the deck.  The order of the deck is fixed, so that is enough.
+func (t *TriGo) positionKey() string {
+	buf := make([]byte, 8*len(t.inField)+8)
+	for i, w := range t.inField {
+		binary.LittleEndian.PutUint64(buf[8*i:], w)
+	}
+	binary.LittleEndian.PutUint64(buf[8*len(t.inField):], uint64(len(t.state.Deck)))
+	return string(buf)
+}
+
+// solve returns whether t can be cleared, and the first match toward that.
+func (s *endgameSolver) solve(t *TriGo) (bool, []int) {
+	matches := t.Matches()
+	if len(matches) == 0 {
+		if len(t.state.Deck) == 0 {
+			for _, c := range t.state.Field {
+				if c >= 0 {
+					return false, nil
+				}
+			}
+			return true, nil
+		}
+		// stuck with cards left, so deal more
+		c := t.searchClone()
+		c.expandField()
+		c.addCards()
+		ok, _ := s.solve(c)
+		return ok, nil
+	}
+
+	key := t.positionKey()
+	if s.failed[key] {
+		return false, nil
+	}
+	if s.budget > 0 && s.nodes >= s.budget {
+		return false, nil
+	}
+	s.nodes++
+
+	for _, m := range matches {
+		c := t.searchClone()
+		c.Remove(m)
+		c.Deal()
+		if ok, _ := s.solve(c); ok {
+			return true, m
+		}
+	}
+	if s.budget == 0 || s.nodes < s.budget {
+		s.failed[key] = true
+	}
+	return false, nil
+}
@@ -0,0 +1,61 @@
+package trigo
+
+import "testing"
+
+// playDown plays t with the first match found until at most n cards are left
+// in the deck.
+func playDown(t *TriGo, n int) {
+	for len(t.state.Deck) > n && !t.Over() {
+		if m := t.FindMatch(); m != nil {
+			t.Claim(m)
+			t.Deal()
+		} else {
+			t.DeclareNoMatch()
+		}
+	}
+}
+
+func TestSolveEndgame(t *testing.T) {
+	solved := 0
+	for seed := int64(1); seed <= 20; seed++ {
+		tri := NewStd()
+		tri.Seed(seed)
+		tri.Shuffle()
+		tri.Deal()
+		playDown(tri, 12)
+		res := tri.SolveEndgame(0)
+		if !res.Complete {
+			t.Fatalf("seed %d: search without a budget didn't finish", seed)
+		}
+		if !res.Clearable {
+			continue
+		}
+		solved++
+
+		// following the solver must clear every card
+		for !tri.Over() {
+			res := tri.SolveEndgame(0)
+			if !res.Clearable {
+				t.Fatalf("seed %d: following the solver lost the clear", seed)
+			}
+			if res.Next == nil {
+				if tri.FindMatch() != nil || !tri.DeclareNoMatch() {
+					t.Fatalf("seed %d: no next match with a match on the field", seed)
+				}
+				continue
+			}
+			if !tri.Claim(res.Next) {
+				t.Fatalf("seed %d: next match %v is not a match", seed, res.Next)
+			}
+			tri.Deal()
+		}
+		for _, c := range tri.state.Field {
+			if c >= 0 {
+				t.Fatalf("seed %d: card %d left after following the solver", seed, c)
+			}
+		}
+	}
+	if solved == 0 {
+		t.Error("no clearable endgames to check")
+	}
+}
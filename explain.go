@@ -0,0 +1,85 @@
+package trigo
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Errors returned for candidates that can't be checked against the match rule.
+var (
+	ErrCandidateSize = errors.New("trigo: candidate size differs from the match size")
+	ErrInvalidSlot   = errors.New("trigo: candidate slot is empty or out of range")
+	ErrRepeatedSlot  = errors.New("trigo: candidate repeats a slot")
+)
+
+// AttrCheck describes how one attribute of a match candidate fares against the
+// match rule.
+type AttrCheck struct {
+	Attr   int   // attribute index
+	Values []int // attribute value of each candidate card, in candidate order
+	Same   bool  // whether the values are all the same
+	Diff   bool  // whether the values are all different
+}
+
+// Pass returns whether the attribute satisfies the match rule.
+func (a AttrCheck) Pass() bool {
+	return a.Same || a.Diff
+}
+
+// Counts returns the number of candidate cards with each attribute value,
+// indexed by value.
+func (a AttrCheck) Counts(numAttrVals int) []int {
+	counts := make([]int, numAttrVals)
+	for _, v := range a.Values {
+		if v >= 0 && v < numAttrVals {
+			counts[v]++
+		}
+	}
+	return counts
+}
+
+// checkCandidate returns an error if candidate can't be a match on the field.
+func (t *TriGo) checkCandidate(candidate []int) error {
+	if len(candidate) != t.state.NumAttrVals {
+		return ErrCandidateSize
+	}
+	for i, f := range candidate {
+		if f < 0 || f >= len(t.state.Field) {
+			return ErrInvalidSlot
+		}
+		if c := t.state.Field[f]; c < 0 || c >= len(t.state.Cards) {
+			return ErrInvalidSlot
+		}
+		for _, g := range candidate[:i] {
+			if f == g {
+				return ErrRepeatedSlot
+			}
+		}
+	}
+	return nil
+}
+
+// Explain checks a match candidate like IsMatch, but returns how each
+// attribute fares against the match rule.  The candidate is a match if every
+// check passes.  An error is returned if the candidate can't be checked.
+func (t *TriGo) Explain(candidate []int) ([]AttrCheck, error) {
+	if err := t.checkCandidate(candidate); err != nil {
+		return nil, err
+	}
+	checks := make([]AttrCheck, t.state.NumAttrs)
+	for i := range checks {
+		check := &checks[i]
+		check.Attr = i
+		check.Values = make([]int, len(candidate))
+		seen := uint64(0)
+		for j, f := range candidate {
+			val := t.state.Cards[t.state.Field[f]].Attr[i]
+			check.Values[j] = val
+			seen |= 1 << uint(val)
+		}
+		numSeen := bits.OnesCount64(seen)
+		check.Same = numSeen == 1
+		check.Diff = numSeen == len(candidate)
+	}
+	return checks, nil
+}
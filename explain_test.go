@@ -0,0 +1,83 @@
+package trigo
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// fieldOf returns a standard game whose field holds cards with the given
+// attribute values.
+func fieldOf(cards ...[]int) *TriGo {
+	tri := NewStd()
+	tri.state.Field = nil
+	for _, attrs := range cards {
+		tri.state.Field = append(tri.state.Field, tri.cardID(attrs))
+	}
+	tri.pack()
+	return tri
+}
+
+func TestExplain(t *testing.T) {
+	match := [][]int{{0, 1, 2, 0}, {1, 1, 0, 0}, {2, 1, 1, 0}}
+	tri := fieldOf(match...)
+	checks, err := tri.Explain([]int{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []AttrCheck{
+		{Attr: 0, Values: []int{0, 1, 2}, Diff: true},
+		{Attr: 1, Values: []int{1, 1, 1}, Same: true},
+		{Attr: 2, Values: []int{2, 0, 1}, Diff: true},
+		{Attr: 3, Values: []int{0, 0, 0}, Same: true},
+	} {
+		if !reflect.DeepEqual(checks[i], want) || !checks[i].Pass() {
+			t.Errorf("match attribute %d: %+v, want %+v", i, checks[i], want)
+		}
+	}
+
+	// spoil each attribute in turn by changing the last card's value
+	for attr := range match[0] {
+		spoiled := append([]int(nil), match[2]...)
+		spoiled[attr] = match[1][attr]
+		if spoiled[attr] == match[0][attr] {
+			spoiled[attr] = (spoiled[attr] + 1) % 3
+		}
+		tri := fieldOf(match[0], match[1], spoiled)
+		checks, err := tri.Explain([]int{0, 1, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, check := range checks {
+			if check.Pass() != (i != attr) {
+				t.Errorf("spoiled attribute %d: attribute %d passes %v", attr, i, check.Pass())
+			}
+		}
+		counts := checks[attr].Counts(3)
+		sort.Ints(counts)
+		if !reflect.DeepEqual(counts, []int{0, 1, 2}) {
+			t.Errorf("spoiled attribute %d: counts %v", attr, checks[attr].Counts(3))
+		}
+		if tri.IsMatch([]int{0, 1, 2}) {
+			t.Errorf("spoiled attribute %d: still a match", attr)
+		}
+	}
+
+	tri = fieldOf(append(match, nil)...)
+	tri.state.Field[3] = -1
+	for _, tc := range []struct {
+		candidate []int
+		err       error
+	}{
+		{[]int{0, 1}, ErrCandidateSize},
+		{[]int{0, 1, 2, 3}, ErrCandidateSize},
+		{[]int{0, 1, 4}, ErrInvalidSlot},
+		{[]int{0, 1, -1}, ErrInvalidSlot},
+		{[]int{0, 1, 3}, ErrInvalidSlot},
+		{[]int{0, 1, 1}, ErrRepeatedSlot},
+	} {
+		if _, err := tri.Explain(tc.candidate); err != tc.err {
+			t.Errorf("Explain(%v) error = %v, want %v", tc.candidate, err, tc.err)
+		}
+	}
+}
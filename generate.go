@@ -0,0 +1,144 @@
+package trigo
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// stdPuzzleBudget is the number of fields GeneratePuzzle tries by default.
+const stdPuzzleBudget = 200000
+
+// ErrNoPuzzle is returned when no puzzle meeting a spec is found within its
+// budget.
+var ErrNoPuzzle = errors.New("trigo: no puzzle found within budget")
+
+// PuzzleSpec describes the puzzles GeneratePuzzle builds.  The field holds
+// Config.FieldSize cards.
+type PuzzleSpec struct {
+	Config     Config
+	Matches    int  // exact number of matches in the field
+	MinAllDiff int  // least number of matches with every attribute different
+	Disjoint   bool // whether no card may be part of more than one match
+	Budget     int  // fields to try before giving up, or 0 for the default
+}
+
+// StdPuzzleSpec returns the spec of the standard puzzle: six matches among
+// twelve cards, one of them all different.
+func StdPuzzleSpec() PuzzleSpec {
+	return PuzzleSpec{Config: StdConfig(), Matches: 6, MinAllDiff: 1}
+}
+
+// Validate returns an error if no field could meet the spec.
+func (s PuzzleSpec) Validate() error {
+	if err := s.Config.Validate(); err != nil {
+		return err
+	}
+	switch {
+	case s.Matches < 1:
+		return fmt.Errorf("trigo: puzzle needs at least 1 match, not %d", s.Matches)
+	case s.MinAllDiff < 0 || s.MinAllDiff > s.Matches:
+		return fmt.Errorf("trigo: puzzle can't have %d all different matches of %d",
+			s.MinAllDiff, s.Matches)
+	case s.Disjoint && s.Matches*s.Config.NumAttrVals > s.Config.FieldSize:
+		return fmt.Errorf("trigo: %d disjoint matches don't fit in %d cards",
+			s.Matches, s.Config.FieldSize)
+	case s.Budget < 0:
+		return fmt.Errorf("trigo: puzzle budget must not be negative, not %d", s.Budget)
+	}
+	return nil
+}
+
+// DailySeed returns the seed of the daily puzzle for the day of date, in its
+// own location.
+func DailySeed(date time.Time) int64 {
+	y, m, d := date.Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// GeneratePuzzle returns a puzzle meeting spec.  The same spec and seed always
+// give the same puzzle.  It searches by swapping single cards in and out of a
+// random field, keeping swaps that bring the field no further from the spec,
+// and starting over from a new field when it stops making progress.
+func GeneratePuzzle(spec PuzzleSpec, seed int64) (*Puzzle, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	budget := spec.Budget
+	if budget == 0 {
+		budget = stdPuzzleBudget
+	}
+	rng := rand.New(rand.NewSource(seed))
+	t := newGame(spec.Config)
+	numCards := len(t.state.Cards)
+	size := spec.Config.FieldSize
+	restartAfter := 20 * numCards
+
+	var field []int
+	cost, stale := 0, 0
+	for tries := 0; tries < budget; tries++ {
+		if field == nil || stale >= restartAfter {
+			field = rng.Perm(numCards)[:size]
+			t.setField(field)
+			cost, stale = t.puzzleCost(spec), 0
+			if cost == 0 {
+				return t.Puzzle(), nil
+			}
+			continue
+		}
+		slot, card := rng.Intn(size), rng.Intn(numCards)
+		if t.isInField(card) {
+			continue
+		}
+		old := field[slot]
+		field[slot] = card
+		t.setField(field)
+		c := t.puzzleCost(spec)
+		if c == 0 {
+			return t.Puzzle(), nil
+		}
+		switch {
+		case c < cost:
+			cost, stale = c, 0
+		case c == cost:
+			stale++
+		default:
+			field[slot] = old
+			t.setField(field)
+			stale++
+		}
+	}
+	return nil, ErrNoPuzzle
+}
+
+// puzzleCost returns how far the field is from meeting spec, or 0 if it does.
+func (t *TriGo) puzzleCost(spec PuzzleSpec) int {
+	matches, allDiff := 0, 0
+	uses := make([]int, len(t.state.Field))
+	t.eachMatch(func(slots []int) bool {
+		matches++
+		if t.allDiff(slots) == t.state.NumAttrs {
+			allDiff++
+		}
+		for _, f := range slots {
+			uses[f]++
+		}
+		return true
+	})
+	cost := matches - spec.Matches
+	if cost < 0 {
+		cost = -cost
+	}
+	if allDiff < spec.MinAllDiff {
+		cost += spec.MinAllDiff - allDiff
+	}
+	if spec.Disjoint {
+		for _, n := range uses {
+			if n > 1 {
+				cost += n - 1
+			}
+		}
+	}
+	return cost
+}
@@ -0,0 +1,51 @@
+package trigo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeneratePuzzle(t *testing.T) {
+	for _, spec := range []PuzzleSpec{
+		StdPuzzleSpec(),
+		{Config: StdConfig(), Matches: 4, MinAllDiff: 2, Disjoint: true},
+		{Config: Config{NumAttrs: 3, NumAttrVals: 5, FieldSize: 15, FieldExpand: 5}, Matches: 2},
+	} {
+		p, err := GeneratePuzzle(spec, 1)
+		if err != nil {
+			t.Fatalf("%+v: %v", spec, err)
+		}
+		if n := len(p.Cards()); n != spec.Config.FieldSize {
+			t.Errorf("%+v: %d cards, want %d", spec, n, spec.Config.FieldSize)
+		}
+		if n := p.NumMatches(); n != spec.Matches {
+			t.Errorf("%+v: %d matches, want %d", spec, n, spec.Matches)
+		}
+		allDiff, used := 0, map[int]bool{}
+		for _, m := range p.matches {
+			if p.t.allDiff(m) == spec.Config.NumAttrs {
+				allDiff++
+			}
+			for _, f := range m {
+				if spec.Disjoint && used[f] {
+					t.Errorf("%+v: slot %d is in more than one match", spec, f)
+				}
+				used[f] = true
+			}
+		}
+		if allDiff < spec.MinAllDiff {
+			t.Errorf("%+v: %d all different matches, want at least %d", spec, allDiff, spec.MinAllDiff)
+		}
+		q, _ := GeneratePuzzle(spec, 1)
+		if !reflect.DeepEqual(p.Cards(), q.Cards()) {
+			t.Errorf("%+v: the same seed gave different puzzles", spec)
+		}
+	}
+
+	bad := StdPuzzleSpec()
+	bad.Disjoint = true
+	bad.Matches = 5
+	if _, err := GeneratePuzzle(bad, 1); err == nil {
+		t.Error("generated disjoint matches that don't fit in the field")
+	}
+}
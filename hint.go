@@ -0,0 +1,77 @@
+package trigo
+
+// HintExists is the first hint level, which only tells whether the field has a
+// match.  Each level above it reveals one more card of a match, up to
+// MaxHintLevel, which reveals the whole match.
+const HintExists = 1
+
+// Hint holds what a hint reveals.
+type Hint struct {
+	Level  int   // hint level, from HintExists to MaxHintLevel
+	Exists bool  // whether the field has a match
+	Slots  []int // field slots of Level-1 cards of a match
+}
+
+// Matches returns the field slots of each match in the field.
+func (t *TriGo) Matches() [][]int {
+	matches := [][]int{}
+	t.eachMatch(func(slots []int) bool {
+		matches = append(matches, append([]int(nil), slots...))
+		return true
+	})
+	return matches
+}
+
+// FindMatch returns the field slots of a match in the field, or nil if there
+// is none.
+func (t *TriGo) FindMatch() []int {
+	var match []int
+	t.eachMatch(func(slots []int) bool {
+		match = append([]int(nil), slots...)
+		return false
+	})
+	return match
+}
+
+// MaxHintLevel returns the hint level that reveals a whole match.
+func (t *TriGo) MaxHintLevel() int {
+	return t.state.NumAttrVals + 1
+}
+
+// Hint returns a hint about the current field, one level above the previous
+// hint, until a whole match has been revealed.  The level starts over when the
+// field changes.
+func (t *TriGo) Hint() Hint {
+	if t.state.HintLevel == 0 {
+		t.state.HintSlots = t.FindMatch()
+	}
+	exists := t.state.HintSlots != nil
+	if t.state.HintLevel < HintExists || (exists && t.state.HintLevel < t.MaxHintLevel()) {
+		t.state.HintLevel++
+		t.state.HintsUsed++
+		level := t.state.HintLevel
+		if exists {
+			t.record(Event{Kind: HintEvent, Slots: t.state.HintSlots[:level-1]})
+		} else {
+			t.record(Event{Kind: HintEvent})
+		}
+	}
+	level := t.state.HintLevel
+	hint := Hint{Level: level, Exists: exists}
+	if exists {
+		hint.Slots = append([]int(nil), t.state.HintSlots[:level-1]...)
+	}
+	return hint
+}
+
+// HintsUsed returns the number of hints given in the current game.  Repeating
+// a hint that reveals nothing new is not counted.
+func (t *TriGo) HintsUsed() int {
+	return t.state.HintsUsed
+}
+
+// resetHint starts hints over after the field changes.
+func (t *TriGo) resetHint() {
+	t.state.HintLevel = 0
+	t.state.HintSlots = nil
+}
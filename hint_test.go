@@ -0,0 +1,60 @@
+package trigo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHint(t *testing.T) {
+	for _, c := range []Config{StdConfig(), {NumAttrs: 3, NumAttrVals: 4, FieldSize: 16, FieldExpand: 4}} {
+		tri, _ := NewFromConfig(c)
+		tri.Deal()
+		max := tri.MaxHintLevel()
+		if max != c.NumAttrVals+1 {
+			t.Fatalf("%+v: MaxHintLevel() = %d, want %d", c, max, c.NumAttrVals+1)
+		}
+		var prev []int
+		for i, want := range []struct {
+			level, slots, used int
+		}{
+			{HintExists, 0, 1},
+			{2, 1, 2},
+			{3, 2, 3},
+			{4, 3, 4},
+			{5, 4, 5},
+			{6, 5, 6},
+		}[:max] {
+			h := tri.Hint()
+			if !h.Exists || h.Level != want.level || len(h.Slots) != want.slots || tri.HintsUsed() != want.used {
+				t.Fatalf("%+v: hint %d = %+v with %d used, want level %d revealing %d cards",
+					c, i, h, tri.HintsUsed(), want.level, want.slots)
+			}
+			if len(prev) > 0 && !reflect.DeepEqual(h.Slots[:len(prev)], prev) {
+				t.Fatalf("%+v: hint %d slots %v don't extend %v", c, i, h.Slots, prev)
+			}
+			prev = h.Slots
+		}
+		if !tri.IsMatch(prev) {
+			t.Errorf("%+v: top hint %v is not a match", c, prev)
+		}
+
+		// asking again reveals nothing new and costs nothing
+		if h := tri.Hint(); h.Level != max || tri.HintsUsed() != max {
+			t.Errorf("%+v: hint past the top = %+v with %d used", c, h, tri.HintsUsed())
+		}
+
+		// the hint starts over when the field changes
+		tri.Claim(prev)
+		tri.Deal()
+		if h := tri.Hint(); h.Level != HintExists {
+			t.Errorf("%+v: hint after a match = %+v, want level %d", c, h, HintExists)
+		}
+	}
+
+	tri := fieldOf([]int{0, 0, 0, 0}, []int{0, 0, 0, 1}, []int{0, 0, 1, 0})
+	for i := 0; i < 2; i++ {
+		if h := tri.Hint(); h.Exists || h.Level != HintExists || h.Slots != nil || tri.HintsUsed() != 1 {
+			t.Errorf("hint %d on a field without a match = %+v with %d used", i, h, tri.HintsUsed())
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package trigo
+
+// record scores e with the scoring policies and adds it to the game log.
+func (t *TriGo) record(e Event) {
+	e.At = t.Elapsed()
+	for _, p := range t.policies {
+		e.Points += p.Points(e)
+	}
+	t.state.Score += e.Points
+	t.state.Log = append(t.state.Log, e)
+}
+
+// recordDeal adds the field after a deal to the game log.
+func (t *TriGo) recordDeal(expanded bool) {
+	t.record(Event{
+		Kind:      DealEvent,
+		Cards:     append([]int(nil), t.state.Field...),
+		Available: t.FieldMatches(),
+		Expanded:  expanded,
+	})
+}
+
+// Log returns the events of the current game, oldest first.  It is saved with
+// the game state.
+func (t *TriGo) Log() []Event {
+	return append([]Event(nil), t.state.Log...)
+}
@@ -0,0 +1,81 @@
+package trigo
+
+// Leftover holds what the sum invariant tells about the cards left over at
+// the end of a game.  With an odd number of attribute values, every match has
+// attribute values summing to 0 modulo the number of values, as does the whole
+// deck, so the cards that remain always sum to 0 as well.
+type Leftover struct {
+	Remaining int // cards on the field and in the deck
+	// Count is the number of leftover cards modulo the match size, since
+	// matches remove cards in groups of that size.
+	Count int
+	// DeckSum holds, for each attribute, the sum of the values of the cards
+	// in the deck modulo the number of values, which is whatever the field
+	// needs to make the remaining cards sum to 0.
+	DeckSum []int
+	// DeckCard is the ID of the last card in the deck, worked out from
+	// DeckSum when only one is left, or -1.
+	DeckCard int
+
+	numAttrVals int
+}
+
+// PredictLeftover works out what the sum invariant tells about the cards that
+// will be left over, from the cards on the field and the size of the deck.
+// ok is false if the game has an even number of attribute values, for which
+// the invariant doesn't hold, or if cards have left the game other than in
+// matches, as when a saved game was started with a partial deck.  Puzzles keep
+// the cards off the field in the deck, so the invariant holds for them.
+func (t *TriGo) PredictLeftover() (l Leftover, ok bool) {
+	numVals := t.state.NumAttrVals
+	if numVals%2 == 0 {
+		return Leftover{}, false
+	}
+	l = Leftover{
+		DeckSum:     make([]int, t.state.NumAttrs),
+		DeckCard:    -1,
+		numAttrVals: numVals,
+	}
+	for _, c := range t.state.Field {
+		if c < 0 {
+			continue
+		}
+		l.Remaining++
+		for i, v := range t.state.Cards[c].Attr {
+			l.DeckSum[i] -= v
+		}
+	}
+	l.Remaining += len(t.state.Deck)
+	if len(t.state.Cards)-l.Remaining != t.state.MatchesFound*numVals {
+		return Leftover{}, false
+	}
+	l.Count = l.Remaining % numVals
+
+	for i := range l.DeckSum {
+		l.DeckSum[i] = (l.DeckSum[i]%numVals + numVals) % numVals
+	}
+	if len(t.state.Deck) == 1 {
+		l.DeckCard = t.cardID(l.DeckSum)
+	}
+	return l, true
+}
+
+// cardID returns the ID of the card with the given attribute values.
+func (t *TriGo) cardID(attrs []int) int {
+	id, place := 0, 1
+	for _, v := range attrs {
+		id += v * place
+		place *= t.state.NumAttrVals
+	}
+	return id
+}
+
+// Possible returns whether a leftover of n cards is consistent with the
+// invariant.
+func (l Leftover) Possible(n int) bool {
+	if n < 0 || n > l.Remaining || l.numAttrVals == 0 || n%l.numAttrVals != l.Count {
+		return false
+	}
+	// three cards whose values sum to 0 are a match
+	return n != 3 || l.numAttrVals != 3
+}
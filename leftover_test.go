@@ -0,0 +1,59 @@
+package trigo
+
+import "testing"
+
+func TestPredictLeftover(t *testing.T) {
+	for _, c := range []Config{StdConfig(), {NumAttrs: 3, NumAttrVals: 5, FieldSize: 15, FieldExpand: 5}} {
+		tri, err := NewFromConfig(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tri.Seed(1)
+		tri.Shuffle()
+		tri.Deal()
+		for {
+			l, ok := tri.PredictLeftover()
+			if !ok {
+				t.Fatalf("%v: no prediction", c)
+			}
+			want := make([]int, c.NumAttrs)
+			for _, card := range tri.state.Deck {
+				for i, v := range tri.state.Cards[card].Attr {
+					want[i] = (want[i] + v) % c.NumAttrVals
+				}
+			}
+			for i := range want {
+				if l.DeckSum[i] != want[i] {
+					t.Fatalf("%v: deck sum %v, want %v", c, l.DeckSum, want)
+				}
+			}
+			if len(tri.state.Deck) == 1 && l.DeckCard != tri.state.Deck[0] {
+				t.Fatalf("%v: last card %d, want %d", c, l.DeckCard, tri.state.Deck[0])
+			}
+			if tri.Over() {
+				if n := l.Remaining; !l.Possible(n) {
+					t.Errorf("%v: leftover of %d cards deemed impossible", c, n)
+				}
+				break
+			}
+			if m := tri.FindMatch(); m != nil {
+				tri.Claim(m)
+				tri.Deal()
+			} else {
+				tri.DeclareNoMatch()
+			}
+		}
+	}
+}
+
+func TestPredictLeftoverPartialDeck(t *testing.T) {
+	tri := NewStd()
+	tri.state.Deck = tri.state.Deck[1:]
+	tri.Deal()
+	if _, ok := tri.PredictLeftover(); ok {
+		t.Error("predicted leftover of a game missing a card")
+	}
+	if _, ok := New(4, 4, 16, 4).PredictLeftover(); ok {
+		t.Error("predicted leftover with an even number of values")
+	}
+}
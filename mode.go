@@ -0,0 +1,146 @@
+package trigo
+
+import (
+	"fmt"
+	"time"
+)
+
+// ModeKind identifies a way of playing a game.
+type ModeKind int
+
+const (
+	Endless    ModeKind = iota // clear the deck at any pace
+	Blitz                      // find as many matches as possible within the limit
+	TimeAttack                 // clear the deck as fast as possible
+	Survival                   // the time left drains, and each match adds to it
+)
+
+var modeNames = []string{"endless", "blitz", "attack", "survival"}
+
+func (k ModeKind) String() string {
+	if k < 0 || int(k) >= len(modeNames) {
+		return fmt.Sprintf("ModeKind(%d)", int(k))
+	}
+	return modeNames[k]
+}
+
+// ParseModeKind returns the mode kind with the given name, as returned by
+// String.
+func ParseModeKind(name string) (ModeKind, error) {
+	for i, n := range modeNames {
+		if n == name {
+			return ModeKind(i), nil
+		}
+	}
+	return Endless, fmt.Errorf("trigo: unknown mode %q", name)
+}
+
+// Mode describes how a game is played and when it ends.
+type Mode struct {
+	Kind  ModeKind
+	Limit time.Duration // time allowed in Blitz, or at the start of Survival
+	Bonus time.Duration // time added for each match in Survival
+}
+
+// StdModes returns a standard mode of each kind.
+func StdModes() []Mode {
+	return []Mode{
+		{Kind: Endless},
+		{Kind: Blitz, Limit: 3 * time.Minute},
+		{Kind: TimeAttack},
+		{Kind: Survival, Limit: time.Minute, Bonus: 10 * time.Second},
+	}
+}
+
+// SetMode sets the mode of the game.  It is saved with the game state and
+// should be set before the game starts.
+func (t *TriGo) SetMode(m Mode) {
+	t.state.Mode = m
+}
+
+// Mode returns the mode of the game.
+func (t *TriGo) Mode() Mode {
+	return t.state.Mode
+}
+
+// timeAllowed returns the total time the mode allows for the game so far, and
+// whether the mode has a time limit at all.
+func (t *TriGo) timeAllowed() (time.Duration, bool) {
+	switch m := t.state.Mode; m.Kind {
+	case Blitz:
+		return m.Limit, true
+	case Survival:
+		return m.Limit + time.Duration(t.state.MatchesFound)*m.Bonus, true
+	}
+	return 0, false
+}
+
+// TimeLeft returns the time left in a timed mode, or 0 for untimed modes.
+func (t *TriGo) TimeLeft() time.Duration {
+	allowed, timed := t.timeAllowed()
+	if left := allowed - t.Elapsed(); timed && left > 0 {
+		return left
+	}
+	return 0
+}
+
+// TimedOut returns whether the time allowed by a timed mode has run out.
+func (t *TriGo) TimedOut() bool {
+	allowed, timed := t.timeAllowed()
+	return timed && t.Elapsed() >= allowed
+}
+
+// cleared returns whether all possible matches have been found, with no cards
+// left to deal and no match on the field.
+func (t *TriGo) cleared() bool {
+	return len(t.state.Deck) == 0 && t.FindMatch() == nil
+}
+
+// gameTime returns the elapsed time of the game, which stops when a timed mode
+// runs out of time.
+func (t *TriGo) gameTime() time.Duration {
+	elapsed := t.Elapsed()
+	if allowed, timed := t.timeAllowed(); timed && elapsed > allowed {
+		return allowed
+	}
+	return elapsed
+}
+
+// Headline summarizes a result in the terms of its mode, as a phrase to follow
+// a subject, such as "found 12 matches in 3m0s".
+func (r Result) Headline() string {
+	elapsed := r.Elapsed.Round(time.Second)
+	switch r.Mode.Kind {
+	case Blitz:
+		return fmt.Sprintf("found %d matches in %s", r.Matches, elapsed)
+	case TimeAttack:
+		return fmt.Sprintf("cleared the deck in %s", elapsed)
+	case Survival:
+		return fmt.Sprintf("survived %s with %d matches", elapsed, r.Matches)
+	}
+	return fmt.Sprintf("found %d matches, scoring %d", r.Matches, r.Score)
+}
+
+// Better returns whether r ranks above o in the terms of r's mode: more
+// matches in Blitz, a faster clear in TimeAttack, a longer time in Survival,
+// and a higher score in Endless.  Ties are broken by score.
+func (r Result) Better(o Result) bool {
+	switch r.Mode.Kind {
+	case Blitz:
+		if r.Matches != o.Matches {
+			return r.Matches > o.Matches
+		}
+	case TimeAttack:
+		if r.Cleared != o.Cleared {
+			return r.Cleared
+		}
+		if r.Elapsed != o.Elapsed {
+			return r.Elapsed < o.Elapsed
+		}
+	case Survival:
+		if r.Elapsed != o.Elapsed {
+			return r.Elapsed > o.Elapsed
+		}
+	}
+	return r.Score > o.Score
+}
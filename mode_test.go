@@ -0,0 +1,90 @@
+package trigo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimedModes(t *testing.T) {
+	modes := StdModes()
+	for _, tc := range []struct {
+		mode  Mode
+		left  time.Duration // time left after 2 matches in 20s
+		timed bool
+	}{
+		{modes[Endless], 0, false},
+		{modes[Blitz], 160 * time.Second, true},
+		{modes[TimeAttack], 0, false},
+		{modes[Survival], 60 * time.Second, true},
+	} {
+		clock := &SimClock{}
+		tri := clockedGame(clock, 1)
+		tri.SetMode(tc.mode)
+		for i := 0; i < 2; i++ {
+			clock.Advance(10 * time.Second)
+			tri.Claim(tri.FindMatch())
+			tri.Deal()
+		}
+		if left := tri.TimeLeft(); left != tc.left {
+			t.Errorf("%v: TimeLeft() = %v, want %v", tc.mode.Kind, left, tc.left)
+		}
+		if tri.TimedOut() {
+			t.Errorf("%v: timed out with time left", tc.mode.Kind)
+		}
+
+		clock.Advance(tc.left)
+		if tri.TimedOut() != tc.timed || tri.Over() != tc.timed {
+			t.Errorf("%v: TimedOut() = %v, Over() = %v at the limit", tc.mode.Kind, tri.TimedOut(), tri.Over())
+		}
+		if !tc.timed {
+			continue
+		}
+		clock.Advance(time.Minute)
+		score := tri.Score()
+		if tri.Claim(tri.FindMatch()) || tri.DeclareNoMatch() || tri.Score() != score {
+			t.Errorf("%v: play counted after time ran out", tc.mode.Kind)
+		}
+		if res := tri.Result(); res.Elapsed != 20*time.Second+tc.left || res.Matches != 2 {
+			t.Errorf("%v: result %+v, want the clock stopped at the limit", tc.mode.Kind, res)
+		}
+	}
+}
+
+func TestHeadline(t *testing.T) {
+	modes := StdModes()
+	for _, tc := range []struct {
+		res  Result
+		want string
+	}{
+		{Result{Mode: modes[Endless], Matches: 20, Score: 300}, "found 20 matches, scoring 300"},
+		{Result{Mode: modes[Blitz], Matches: 12, Elapsed: 3 * time.Minute}, "found 12 matches in 3m0s"},
+		{Result{Mode: modes[TimeAttack], Elapsed: 5*time.Minute + 400*time.Millisecond}, "cleared the deck in 5m0s"},
+		{Result{Mode: modes[Survival], Matches: 9, Elapsed: 150 * time.Second}, "survived 2m30s with 9 matches"},
+	} {
+		if got := tc.res.Headline(); got != tc.want {
+			t.Errorf("%v: Headline() = %q, want %q", tc.res.Mode.Kind, got, tc.want)
+		}
+	}
+}
+
+func TestBetter(t *testing.T) {
+	modes := StdModes()
+	for _, tc := range []struct {
+		name        string
+		better, not Result
+	}{
+		{"blitz matches", Result{Mode: modes[Blitz], Matches: 10}, Result{Mode: modes[Blitz], Matches: 9, Score: 500}},
+		{"blitz score", Result{Mode: modes[Blitz], Matches: 10, Score: 200}, Result{Mode: modes[Blitz], Matches: 10, Score: 150}},
+		{"attack cleared", Result{Mode: modes[TimeAttack], Cleared: true, Elapsed: time.Hour},
+			Result{Mode: modes[TimeAttack], Elapsed: time.Minute}},
+		{"attack faster", Result{Mode: modes[TimeAttack], Cleared: true, Elapsed: 4 * time.Minute},
+			Result{Mode: modes[TimeAttack], Cleared: true, Elapsed: 5 * time.Minute, Score: 500}},
+		{"survival longer", Result{Mode: modes[Survival], Elapsed: 3 * time.Minute},
+			Result{Mode: modes[Survival], Elapsed: 2 * time.Minute, Score: 500}},
+		{"endless score", Result{Mode: modes[Endless], Score: 300}, Result{Mode: modes[Endless], Matches: 30, Score: 200}},
+	} {
+		if !tc.better.Better(tc.not) || tc.not.Better(tc.better) {
+			t.Errorf("%s: %+v doesn't rank above %+v", tc.name, tc.better, tc.not)
+		}
+	}
+}
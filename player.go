@@ -0,0 +1,137 @@
+package trigo
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ActionKind identifies what a player does on a turn.
+type ActionKind int
+
+const (
+	ClaimAction   ActionKind = iota // claim a match
+	NoMatchAction                   // declare the field has no match
+	HintAction                      // ask for a hint
+)
+
+// Action is what a player does next.
+type Action struct {
+	Kind  ActionKind
+	Slots []int         // field slots of the claimed match, for ClaimAction
+	Delay time.Duration // game time the player takes before acting
+}
+
+// A Player decides what to do next in a game.  The game passed to Act is a
+// copy, which the player may change freely.
+type Player interface {
+	Act(t *TriGo) Action
+}
+
+// BotSkill describes how well a Bot plays.
+type BotSkill struct {
+	ReactMean   time.Duration // mean time to act
+	ReactStdDev time.Duration // standard deviation of the time to act
+	DiffDelay   time.Duration // extra time for each all-different attribute of a match
+	// Miss is the probability of overlooking a match, indexed by the number of
+	// its attributes that are all different.  Higher counts use the last value.
+	Miss []float64
+	// PreferEasy is the probability of taking the match with the fewest
+	// all-different attributes, rather than any match noticed.
+	PreferEasy float64
+}
+
+// NoviceSkill returns the skill of a beginner.
+func NoviceSkill() BotSkill {
+	return BotSkill{
+		ReactMean:   20 * time.Second,
+		ReactStdDev: 8 * time.Second,
+		DiffDelay:   4 * time.Second,
+		Miss:        []float64{0.2, 0.3, 0.45, 0.6, 0.75},
+		PreferEasy:  0.8,
+	}
+}
+
+// ExpertSkill returns the skill of a practiced player.
+func ExpertSkill() BotSkill {
+	return BotSkill{
+		ReactMean:   5 * time.Second,
+		ReactStdDev: 2 * time.Second,
+		DiffDelay:   time.Second,
+		Miss:        []float64{0.02, 0.05, 0.08, 0.12, 0.15},
+		PreferEasy:  0.3,
+	}
+}
+
+// PerfectSkill returns the skill of a bot that sees every match at once.
+func PerfectSkill() BotSkill {
+	return BotSkill{}
+}
+
+// missProb returns the probability of overlooking a match with the given
+// number of all-different attributes.
+func (s BotSkill) missProb(allDiff int) float64 {
+	if len(s.Miss) == 0 {
+		return 0
+	}
+	if allDiff >= len(s.Miss) {
+		return s.Miss[len(s.Miss)-1]
+	}
+	return s.Miss[allDiff]
+}
+
+// Bot is a Player that finds matches with the game's match finder, limited by
+// its skill.
+type Bot struct {
+	Skill BotSkill
+	rng   *rand.Rand
+}
+
+// NewBot returns a bot with the given skill, whose choices are determined by
+// seed.
+func NewBot(skill BotSkill, seed int64) *Bot {
+	return &Bot{Skill: skill, rng: rand.New(rand.NewSource(seed))}
+}
+
+// reactionTime returns a random time to act.
+func (b *Bot) reactionTime() time.Duration {
+	d := b.Skill.ReactMean + time.Duration(b.rng.NormFloat64()*float64(b.Skill.ReactStdDev))
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Act looks for matches on the field, overlooking some as its skill dictates.
+// It claims one of the matches it notices, asks for a hint if it noticed none,
+// or declares there is no match if there really is none.
+func (b *Bot) Act(t *TriGo) Action {
+	delay := b.reactionTime()
+	matches := t.Matches()
+	if len(matches) == 0 {
+		return Action{Kind: NoMatchAction, Delay: delay}
+	}
+
+	seen, diffs := [][]int{}, []int{}
+	easiest := -1
+	for _, m := range matches {
+		diff := t.allDiff(m)
+		if b.rng.Float64() < b.Skill.missProb(diff) {
+			continue
+		}
+		if easiest < 0 || diff < diffs[easiest] {
+			easiest = len(seen)
+		}
+		seen = append(seen, m)
+		diffs = append(diffs, diff)
+	}
+	if len(seen) == 0 {
+		return Action{Kind: HintAction, Delay: delay}
+	}
+
+	pick := easiest
+	if b.rng.Float64() >= b.Skill.PreferEasy {
+		pick = b.rng.Intn(len(seen))
+	}
+	delay += time.Duration(diffs[pick]) * b.Skill.DiffDelay
+	return Action{Kind: ClaimAction, Slots: seen[pick], Delay: delay}
+}
@@ -0,0 +1,138 @@
+package trigo
+
+import (
+	"errors"
+	"sort"
+)
+
+// Errors returned for puzzle claims.
+var (
+	ErrNotMatch     = errors.New("trigo: candidate is not a match")
+	ErrAlreadyFound = errors.New("trigo: match already found")
+)
+
+// Puzzle is a fixed field in which every match must be found.  Nothing is
+// dealt or removed.
+type Puzzle struct {
+	t       *TriGo
+	matches [][]int // field slots of each match, in ascending order
+	found   []int   // indices in matches, in the order found
+}
+
+// NewPuzzle returns a puzzle whose field holds the cards with the given IDs.
+func NewPuzzle(c Config, cards []int) (*Puzzle, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	t := newGame(c)
+	if len(cards) < c.NumAttrVals || !t.validCards(cards) {
+		return nil, errors.New("trigo: puzzle cards must be distinct, valid, and at least a match")
+	}
+	t.setField(cards)
+	return t.Puzzle(), nil
+}
+
+// Puzzle returns a puzzle of the cards on the current field.
+func (t *TriGo) Puzzle() *Puzzle {
+	c := t.searchClone()
+	return &Puzzle{t: c, matches: c.Matches()}
+}
+
+// setField replaces the field with the given cards, leaving the rest in the
+// deck.
+func (t *TriGo) setField(cards []int) {
+	t.state.Field = append([]int(nil), cards...)
+	t.state.Deck = t.state.Deck[:0]
+	for i := range t.inField {
+		t.inField[i] = 0
+	}
+	for _, c := range cards {
+		t.inField[c/64] |= 1 << uint(c%64)
+	}
+	for c := range t.state.Cards {
+		if !t.isInField(c) {
+			t.state.Deck = append(t.state.Deck, c)
+		}
+	}
+	t.resetHint()
+}
+
+// Field returns the cards of the puzzle.
+func (p *Puzzle) Field() []Card {
+	return p.t.Field()
+}
+
+// Cards returns the IDs of the cards of the puzzle.
+func (p *Puzzle) Cards() []int {
+	return append([]int(nil), p.t.state.Field...)
+}
+
+// Config returns the configuration of the puzzle's cards.
+func (p *Puzzle) Config() Config {
+	return p.t.Config()
+}
+
+// NumMatches returns the number of matches in the puzzle.
+func (p *Puzzle) NumMatches() int {
+	return len(p.matches)
+}
+
+// Found returns the field slots of each match found so far, in the order they
+// were found.
+func (p *Puzzle) Found() [][]int {
+	found := make([][]int, len(p.found))
+	for i, m := range p.found {
+		found[i] = append([]int(nil), p.matches[m]...)
+	}
+	return found
+}
+
+// Done returns whether every match has been found.
+func (p *Puzzle) Done() bool {
+	return len(p.found) == len(p.matches)
+}
+
+// Explain explains a candidate like TriGo.Explain.
+func (p *Puzzle) Explain(slots []int) ([]AttrCheck, error) {
+	return p.t.Explain(slots)
+}
+
+// Claim records a match found in the puzzle.  It returns ErrNotMatch if the
+// candidate is not a match, ErrAlreadyFound if it was found before, or one of
+// the errors of Explain if the candidate can't be checked.
+func (p *Puzzle) Claim(slots []int) error {
+	if err := p.t.checkCandidate(slots); err != nil {
+		return err
+	}
+	if !p.t.IsMatch(slots) {
+		return ErrNotMatch
+	}
+	sorted := append([]int(nil), slots...)
+	sort.Ints(sorted)
+	for i, m := range p.matches {
+		if !equalInts(m, sorted) {
+			continue
+		}
+		for _, f := range p.found {
+			if f == i {
+				return ErrAlreadyFound
+			}
+		}
+		p.found = append(p.found, i)
+		return nil
+	}
+	return ErrNotMatch
+}
+
+// equalInts returns whether a and b hold the same values in the same order.
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
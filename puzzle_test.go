@@ -0,0 +1,74 @@
+package trigo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPuzzleClaim(t *testing.T) {
+	p, err := GeneratePuzzle(StdPuzzleSpec(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPuzzle(StdConfig(), p.Cards()); err != nil {
+		t.Fatal(err)
+	}
+	matches := p.t.Matches()
+	if len(matches) != p.NumMatches() {
+		t.Fatalf("puzzle has %d matches, field has %d", p.NumMatches(), len(matches))
+	}
+
+	for _, tc := range []struct {
+		slots []int
+		err   error
+	}{
+		{nonMatch(p.t), ErrNotMatch},
+		{[]int{0, 1}, ErrCandidateSize},
+		{[]int{0, 1, 12}, ErrInvalidSlot},
+		{[]int{0, 0, 1}, ErrRepeatedSlot},
+	} {
+		if err := p.Claim(tc.slots); err != tc.err {
+			t.Errorf("Claim(%v) = %v, want %v", tc.slots, err, tc.err)
+		}
+	}
+
+	for i := len(matches) - 1; i >= 0; i-- {
+		if p.Done() {
+			t.Fatalf("done with %d matches left", i+1)
+		}
+		m := matches[i]
+		// the slots of a match may be given in any order
+		reversed := []int{m[2], m[1], m[0]}
+		if err := p.Claim(reversed); err != nil {
+			t.Fatalf("Claim(%v) = %v", reversed, err)
+		}
+		if err := p.Claim(m); err != ErrAlreadyFound {
+			t.Errorf("Claim(%v) again = %v, want %v", m, err, ErrAlreadyFound)
+		}
+	}
+	if !p.Done() {
+		t.Error("not done after finding every match")
+	}
+	found := p.Found()
+	for i, m := range found {
+		if want := matches[len(matches)-1-i]; !reflect.DeepEqual(m, want) {
+			t.Errorf("found match %d = %v, want %v", i, m, want)
+		}
+	}
+}
+
+func TestNewPuzzle(t *testing.T) {
+	for _, cards := range [][]int{{0, 1}, {0, 1, 1}, {0, 1, 81}} {
+		if _, err := NewPuzzle(StdConfig(), cards); err == nil {
+			t.Errorf("NewPuzzle(%v) made a puzzle", cards)
+		}
+	}
+	// a puzzle doesn't change when its matches are claimed
+	p, err := NewPuzzle(StdConfig(), []int{0, 1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.NumMatches() != 1 || p.Claim([]int{0, 1, 2}) != nil || !reflect.DeepEqual(p.Cards(), []int{0, 1, 2, 3}) {
+		t.Errorf("puzzle of cards 0 to 3: %d matches, cards %v", p.NumMatches(), p.Cards())
+	}
+}
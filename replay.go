@@ -0,0 +1,119 @@
+package trigo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by Replay.Verify.
+var (
+	ErrInvalidMove    = errors.New("trigo: invalid move")
+	ErrReplayMismatch = errors.New("trigo: replay does not give the claimed result")
+	ErrUnfinished     = errors.New("trigo: replay does not finish the game")
+)
+
+// resultDelay is how long after the last move a finished game may claim to have
+// ended.
+const resultDelay = time.Second
+
+// Move is something a player did in a game, for replaying it.
+type Move struct {
+	Kind  EventKind     // MatchEvent, InvalidClaimEvent, FalseNoMatchEvent, NoMatchEvent or HintEvent
+	At    time.Duration // game clock time of the move
+	Slots []int         // field slots claimed
+}
+
+// Replay holds what is needed to play a game again exactly: the game was
+// seeded with Seed just before it was shuffled, dealt the deck in order, and
+// was scored with StdScoring.
+type Replay struct {
+	Config Config
+	Mode   Mode
+	Seed   int64
+	Moves  []Move
+}
+
+// Replay returns the replay of the current game, which must have been seeded
+// with seed just before it was shuffled.
+func (t *TriGo) Replay(seed int64) Replay {
+	r := Replay{Config: t.Config(), Mode: t.state.Mode, Seed: seed}
+	for _, e := range t.state.Log {
+		switch e.Kind {
+		case MatchEvent, InvalidClaimEvent, FalseNoMatchEvent, NoMatchEvent, HintEvent:
+			r.Moves = append(r.Moves, Move{Kind: e.Kind, At: e.At, Slots: e.Slots})
+		}
+	}
+	return r
+}
+
+// Verify plays the game again and checks that every move is valid and that the
+// game ends with the claimed result.  Claims recorded as matches must be
+// matches, and those recorded as invalid must not be.  Every field of the
+// claimed result must match the replay's.  The elapsed time must be exact for
+// a game that ran out of time, and may be at most a second after the last move
+// otherwise.  It returns the result of the replayed game.
+func (r Replay) Verify(claimed Result) (Result, error) {
+	if err := r.Config.Validate(); err != nil {
+		return Result{}, err
+	}
+	clock := &SimClock{}
+	t := newGame(r.Config)
+	t.SetTimeSource(clock.Now)
+	t.SetScoring(StdScoring()...)
+	t.SetMode(r.Mode)
+	t.Seed(r.Seed)
+	t.Shuffle()
+	t.Deal()
+
+	var at time.Duration
+	for i, m := range r.Moves {
+		if m.At < at {
+			return Result{}, fmt.Errorf("%w %d: out of order", ErrInvalidMove, i)
+		}
+		clock.Advance(m.At - at)
+		at = m.At
+		ok := true
+		switch m.Kind {
+		case MatchEvent:
+			ok = t.Claim(m.Slots)
+			if ok {
+				t.Deal()
+			}
+		case InvalidClaimEvent:
+			ok = !t.TimedOut() && !t.Claim(m.Slots)
+		case NoMatchEvent:
+			ok = t.DeclareNoMatch()
+		case FalseNoMatchEvent:
+			ok = !t.TimedOut() && !t.DeclareNoMatch()
+		case HintEvent:
+			t.Hint()
+		default:
+			ok = false
+		}
+		if !ok {
+			return Result{}, fmt.Errorf("%w %d", ErrInvalidMove, i)
+		}
+	}
+
+	if !t.Over() {
+		allowed, timed := t.timeAllowed()
+		if !timed {
+			return Result{}, ErrUnfinished
+		}
+		clock.Advance(allowed - at)
+	}
+	res := t.Result()
+	// a game that ran out of time ends exactly at the limit, but one that
+	// finished may report its result a little after the last move
+	late := claimed.Elapsed - res.Elapsed
+	if late != 0 && (t.TimedOut() || late < 0 || late > resultDelay) {
+		return res, ErrReplayMismatch
+	}
+	c := claimed
+	c.Elapsed = res.Elapsed
+	if c != res {
+		return res, ErrReplayMismatch
+	}
+	return res, nil
+}
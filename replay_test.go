@@ -0,0 +1,79 @@
+package trigo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// playRecorded plays a seeded game in mode with a bot, after a hint and an
+// invalid claim, and returns the game and its result.
+func playRecorded(mode Mode, seed int64) (*TriGo, Result) {
+	clock := &SimClock{}
+	t := NewStd()
+	t.SetTimeSource(clock.Now)
+	t.SetScoring(StdScoring()...)
+	t.SetMode(mode)
+	t.Seed(seed)
+	t.Shuffle()
+	t.Deal()
+	t.Hint()
+	for _, slots := range [][]int{{0, 1, 2}, {0, 1, 3}} {
+		if !t.IsMatch(slots) {
+			t.Claim(slots)
+			break
+		}
+	}
+	d := Driver{Game: t, Players: []Player{NewBot(NoviceSkill(), seed)}, Clock: clock}
+	return t, d.Run().Result
+}
+
+func TestReplayVerify(t *testing.T) {
+	for _, mode := range StdModes() {
+		tri, res := playRecorded(mode, 1)
+		r := tri.Replay(1)
+		if got, err := r.Verify(res); err != nil || got != res {
+			t.Fatalf("%v: Verify = %v, %v, want %v", mode.Kind, got, err, res)
+		}
+
+		forged := map[string]func(*Result){
+			"score":          func(r *Result) { r.Score += 10 },
+			"matches":        func(r *Result) { r.Matches++ },
+			"leftover":       func(r *Result) { r.Leftover++ },
+			"hints":          func(r *Result) { r.Hints-- },
+			"invalid claims": func(r *Result) { r.InvalidClaims-- },
+			"false no match": func(r *Result) { r.FalseNoMatches++ },
+			"earlier end":    func(r *Result) { r.Elapsed -= time.Millisecond },
+			"later end":      func(r *Result) { r.Elapsed += 10 * time.Hour },
+		}
+		if mode.Kind == Blitz || mode.Kind == Survival {
+			forged["end after time ran out"] = func(r *Result) { r.Elapsed += time.Millisecond }
+		}
+		for name, forge := range forged {
+			claimed := res
+			forge(&claimed)
+			if _, err := r.Verify(claimed); err != ErrReplayMismatch {
+				t.Errorf("%v: forged %s: err = %v, want %v", mode.Kind, name, err, ErrReplayMismatch)
+			}
+		}
+
+		for i, m := range r.Moves {
+			if m.Kind == MatchEvent {
+				r.Moves[i].Slots = []int{0, 1, 0}
+				break
+			}
+		}
+		if _, err := r.Verify(res); !errors.Is(err, ErrInvalidMove) {
+			t.Errorf("%v: tampered move: err = %v, want %v", mode.Kind, err, ErrInvalidMove)
+		}
+	}
+}
+
+func TestReplayVerifyReportDelay(t *testing.T) {
+	tri, res := playRecorded(StdModes()[TimeAttack], 1)
+	r := tri.Replay(1)
+	res.Elapsed += resultDelay / 2
+	if _, err := r.Verify(res); err != nil {
+		t.Errorf("result reported after the last move: %v", err)
+	}
+}
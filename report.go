@@ -0,0 +1,174 @@
+package trigo
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// Report analyzes a game from its log.
+type Report struct {
+	Deals []DealReport
+	// Patterns summarizes matches by the pattern of each attribute: all the
+	// same, then all different, for each attribute in turn.
+	Patterns []PatternStats
+}
+
+// DealReport describes what happened between one deal and the next.  Cards
+// are given by ID.
+type DealReport struct {
+	At             time.Duration // game time of the deal
+	Field          []int         // the field after the deal, with -1 for blank slots
+	Expanded       bool          // whether the field had to be expanded
+	Available      [][]int       // every match on the field
+	Taken          []int         // the match claimed, or nil if none was
+	Missed         [][]int       // the matches on the field other than the one taken
+	Time           time.Duration // time from the deal to the claim, or to the next deal
+	InvalidClaims  int
+	Hints          int
+	FalseNoMatches int
+}
+
+// PatternStats summarizes the matches in which one attribute is all the same
+// or all different.
+type PatternStats struct {
+	Attr      int
+	AllDiff   bool
+	Available int           // matches of the pattern on the field after deals
+	Taken     int           // matches of the pattern taken
+	Time      time.Duration // total time to take the matches taken
+}
+
+// MeanTime returns the mean time to take a match of the pattern.
+func (p PatternStats) MeanTime() time.Duration {
+	if p.Taken == 0 {
+		return 0
+	}
+	return p.Time / time.Duration(p.Taken)
+}
+
+// Report analyzes the current game from its log.
+func (t *TriGo) Report() Report {
+	s := t.searchClone()
+	r := Report{Patterns: make([]PatternStats, 2*t.state.NumAttrs)}
+	for i := range r.Patterns {
+		r.Patterns[i] = PatternStats{Attr: i / 2, AllDiff: i%2 == 1}
+	}
+	var d *DealReport
+	var end time.Duration
+	finish := func() {
+		if d == nil {
+			return
+		}
+		if d.Taken == nil {
+			d.Time = end - d.At
+		}
+		for _, m := range d.Available {
+			if !equalInts(m, d.Taken) {
+				d.Missed = append(d.Missed, m)
+			}
+			for _, p := range s.patterns(m) {
+				r.Patterns[p].Available++
+			}
+		}
+		for _, p := range s.patterns(d.Taken) {
+			r.Patterns[p].Taken++
+			r.Patterns[p].Time += d.Time
+		}
+	}
+	for _, e := range t.state.Log {
+		end = e.At
+		if e.Kind == DealEvent {
+			finish()
+			r.Deals = append(r.Deals, DealReport{
+				At:        e.At,
+				Field:     append([]int(nil), e.Cards...),
+				Expanded:  e.Expanded,
+				Available: s.matchesAmong(e.Cards),
+			})
+			d = &r.Deals[len(r.Deals)-1]
+			continue
+		}
+		if d == nil {
+			continue
+		}
+		switch e.Kind {
+		case MatchEvent:
+			if d.Taken == nil {
+				d.Taken = sortedCopy(e.Cards)
+				d.Time = e.At - d.At
+			}
+		case InvalidClaimEvent:
+			d.InvalidClaims++
+		case HintEvent:
+			d.Hints++
+		case FalseNoMatchEvent:
+			d.FalseNoMatches++
+		}
+	}
+	if g := t.gameTime(); g > end {
+		end = g
+	}
+	finish()
+	return r
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// matchesAmong returns the sorted card IDs of each match among the given
+// cards, skipping blanks.  It replaces the field of t.
+func (t *TriGo) matchesAmong(cards []int) [][]int {
+	field := []int{}
+	for _, c := range cards {
+		if c >= 0 {
+			field = append(field, c)
+		}
+	}
+	t.setField(field)
+	matches := [][]int{}
+	t.eachMatch(func(slots []int) bool {
+		m := make([]int, len(slots))
+		for i, f := range slots {
+			m[i] = field[f]
+		}
+		matches = append(matches, sortedCopy(m))
+		return true
+	})
+	return matches
+}
+
+// patterns returns the index in Report.Patterns of the pattern of each
+// attribute of a match, given by card IDs.
+func (t *TriGo) patterns(match []int) []int {
+	if len(match) == 0 {
+		return nil
+	}
+	pats := make([]int, t.state.NumAttrs)
+	for a := range pats {
+		first := t.state.Cards[match[0]].Attr[a]
+		same := true
+		for _, c := range match[1:] {
+			if t.state.Cards[c].Attr[a] != first {
+				same = false
+			}
+		}
+		pats[a] = 2 * a
+		if !same {
+			pats[a]++
+		}
+	}
+	return pats
+}
+
+// sortedCopy returns a sorted copy of s.
+func sortedCopy(s []int) []int {
+	c := append([]int(nil), s...)
+	sort.Ints(c)
+	return c
+}
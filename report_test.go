@@ -0,0 +1,110 @@
+package trigo
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fieldMatches returns the sorted card IDs of each match on the field.
+func fieldMatches(t *TriGo) [][]int {
+	matches := [][]int{}
+	for _, m := range t.Matches() {
+		cards := make([]int, len(m))
+		for i, f := range m {
+			cards[i] = t.state.Field[f]
+		}
+		sort.Ints(cards)
+		matches = append(matches, cards)
+	}
+	return matches
+}
+
+func TestReport(t *testing.T) {
+	clock := &SimClock{}
+	tri := clockedGame(clock, 5)
+	want := []DealReport{{Available: fieldMatches(tri)}}
+	claim := func(wait time.Duration) {
+		clock.Advance(wait)
+		d := &want[len(want)-1]
+		slots := tri.FindMatch()
+		for _, f := range slots {
+			d.Taken = append(d.Taken, tri.state.Field[f])
+		}
+		sort.Ints(d.Taken)
+		d.Time = tri.Elapsed() - d.At
+		if !tri.Claim(slots) {
+			t.Fatal("claim failed")
+		}
+		tri.Deal()
+		want = append(want, DealReport{At: tri.Elapsed(), Available: fieldMatches(tri)})
+	}
+
+	clock.Advance(3 * time.Second)
+	tri.Claim(nonMatch(tri))
+	tri.Hint()
+	want[0].InvalidClaims, want[0].Hints = 1, 1
+	claim(4 * time.Second)
+	tri.DeclareNoMatch()
+	want[1].FalseNoMatches = 1
+	claim(5 * time.Second)
+	clock.Advance(2 * time.Second)
+	want[2].Time = 2 * time.Second
+
+	r := tri.Report()
+	if len(r.Deals) != len(want) {
+		t.Fatalf("%d deals, want %d", len(r.Deals), len(want))
+	}
+	for i, d := range r.Deals {
+		w := want[i]
+		if d.At != w.At || d.Time != w.Time {
+			t.Errorf("deal %d: at %v taking %v, want at %v taking %v", i, d.At, d.Time, w.At, w.Time)
+		}
+		if !reflect.DeepEqual(d.Available, w.Available) || !reflect.DeepEqual(d.Taken, w.Taken) {
+			t.Errorf("deal %d: took %v of %v, want %v of %v", i, d.Taken, d.Available, w.Taken, w.Available)
+		}
+		missed := len(w.Available)
+		if w.Taken != nil {
+			missed--
+		}
+		if len(d.Missed) != missed {
+			t.Errorf("deal %d: missed %d matches, want %d", i, len(d.Missed), missed)
+		}
+		if d.InvalidClaims != w.InvalidClaims || d.Hints != w.Hints || d.FalseNoMatches != w.FalseNoMatches {
+			t.Errorf("deal %d: %d invalid claims, %d hints and %d false no matches, want %d, %d and %d", i,
+				d.InvalidClaims, d.Hints, d.FalseNoMatches, w.InvalidClaims, w.Hints, w.FalseNoMatches)
+		}
+	}
+
+	pats := make([]PatternStats, 2*tri.state.NumAttrs)
+	for i := range pats {
+		pats[i].Attr, pats[i].AllDiff = i/2, i%2 == 1
+	}
+	// pattern returns the pattern of attribute a of match m.
+	pattern := func(m []int, a int) *PatternStats {
+		if tri.state.Cards[m[0]].Attr[a] == tri.state.Cards[m[1]].Attr[a] {
+			return &pats[2*a]
+		}
+		return &pats[2*a+1]
+	}
+	for _, w := range want {
+		for a := 0; a < tri.state.NumAttrs; a++ {
+			for _, m := range w.Available {
+				pattern(m, a).Available++
+			}
+			if w.Taken != nil {
+				pattern(w.Taken, a).Taken++
+				pattern(w.Taken, a).Time += w.Time
+			}
+		}
+	}
+	if !reflect.DeepEqual(r.Patterns, pats) {
+		t.Errorf("patterns %+v, want %+v", r.Patterns, pats)
+	}
+	for _, p := range r.Patterns {
+		if p.Taken > 0 && p.MeanTime() != p.Time/time.Duration(p.Taken) {
+			t.Errorf("%+v: mean time %v", p, p.MeanTime())
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package trigo
+
+import (
+	"math/bits"
+	"time"
+)
+
+// EventKind identifies something a player did.
+type EventKind int
+
+const (
+	MatchEvent EventKind = iota
+	InvalidClaimEvent
+	FalseNoMatchEvent
+	HintEvent
+	NoMatchEvent // a correct no-match declaration
+	DealEvent    // the field changed after a deal
+)
+
+// Event describes something that happened in a game, for scoring and the game
+// log.
+type Event struct {
+	Kind    EventKind
+	At      time.Duration // game clock time of the event
+	Slots   []int         // field slots claimed, or revealed by a hint
+	Cards   []int         // IDs of the cards claimed, or of the field after a deal
+	AllDiff int           // for matches, number of attributes all different
+	Elapsed time.Duration // for matches, time taken to find the match
+	Points  int           // points scored for the event
+
+	Available int  // for deals, number of matches on the field
+	Expanded  bool // for deals, whether the field had to be expanded
+}
+
+// A ScorePolicy awards points for events.  Negative points are penalties.
+type ScorePolicy interface {
+	Points(e Event) int
+}
+
+// MatchPoints awards Base points for each match, plus PerDiff points for each
+// attribute whose values are all different.
+type MatchPoints struct {
+	Base    int
+	PerDiff int
+}
+
+func (p MatchPoints) Points(e Event) int {
+	if e.Kind != MatchEvent {
+		return 0
+	}
+	return p.Base + p.PerDiff*e.AllDiff
+}
+
+// TimeBonus awards PerSecond points for each whole second under Par that a
+// match took to find.
+type TimeBonus struct {
+	Par       time.Duration
+	PerSecond int
+}
+
+func (p TimeBonus) Points(e Event) int {
+	if e.Kind != MatchEvent || e.Elapsed >= p.Par {
+		return 0
+	}
+	return p.PerSecond * int((p.Par-e.Elapsed)/time.Second)
+}
+
+// Penalties deducts points for invalid claims, false no-match declarations and
+// hints.
+type Penalties struct {
+	InvalidClaim int
+	FalseNoMatch int
+	Hint         int
+}
+
+func (p Penalties) Points(e Event) int {
+	switch e.Kind {
+	case InvalidClaimEvent:
+		return -p.InvalidClaim
+	case FalseNoMatchEvent:
+		return -p.FalseNoMatch
+	case HintEvent:
+		return -p.Hint
+	}
+	return 0
+}
+
+// StdScoring returns the scoring policies of a standard game.
+func StdScoring() []ScorePolicy {
+	return []ScorePolicy{
+		MatchPoints{Base: 10, PerDiff: 5},
+		TimeBonus{Par: 30 * time.Second, PerSecond: 1},
+		Penalties{InvalidClaim: 5, FalseNoMatch: 10, Hint: 3},
+	}
+}
+
+// SetScoring sets the policies that score the game.  The score is the sum of
+// the points all policies award.  Policies are not saved with the game state,
+// so they must be set again on a restored game.
+func (t *TriGo) SetScoring(policies ...ScorePolicy) {
+	t.policies = policies
+}
+
+// Score returns the score of the current game.
+func (t *TriGo) Score() int {
+	return t.state.Score
+}
+
+// allDiff returns the number of attributes whose values are all different
+// across the cards in the given field slots.
+func (t *TriGo) allDiff(slots []int) int {
+	numVals := uint(t.state.NumAttrVals)
+	full := uint64(1)<<numVals - 1
+	n := 0
+	for w := 0; w < t.words; w++ {
+		m := uint64(0)
+		for _, f := range slots {
+			m |= t.attrBits[t.state.Field[f]*t.words+w]
+		}
+		for i := 0; i < t.attrsInWord(w); i++ {
+			if bits.OnesCount64(m>>(uint(i)*numVals)&full) == len(slots) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Claim checks a match candidate, scores the claim, and removes the match from
+// the field if it is valid.  It returns whether the candidate was a match.
+// Deal should be called after a successful claim.  Claims made after a timed
+// mode runs out of time are ignored.
+func (t *TriGo) Claim(candidate []int) bool {
+	if t.TimedOut() {
+		return false
+	}
+	slots := append([]int(nil), candidate...)
+	cards := make([]int, len(slots))
+	for i, f := range slots {
+		cards[i] = t.FieldCardID(f)
+	}
+	if !t.IsMatch(candidate) {
+		t.state.InvalidClaims++
+		t.record(Event{Kind: InvalidClaimEvent, Slots: slots, Cards: cards})
+		return false
+	}
+	e := Event{
+		Kind:    MatchEvent,
+		Slots:   slots,
+		Cards:   cards,
+		AllDiff: t.allDiff(candidate),
+		Elapsed: t.timeMatch(),
+	}
+	t.Remove(candidate)
+	t.record(e)
+	return true
+}
+
+// DeclareNoMatch claims that the field has no match.  If that's so, the field
+// is expanded with more cards, if any are left; otherwise the false
+// declaration is scored.  It returns whether the declaration was right.
+// Declarations made after a timed mode runs out of time are ignored.
+func (t *TriGo) DeclareNoMatch() bool {
+	if t.TimedOut() {
+		return false
+	}
+	if t.FindMatch() != nil {
+		t.state.FalseNoMatches++
+		t.record(Event{Kind: FalseNoMatchEvent})
+		return false
+	}
+	t.record(Event{Kind: NoMatchEvent})
+	if len(t.state.Deck) > 0 {
+		t.resetHint()
+		t.expandField()
+		t.addCards()
+		t.recordDeal(true)
+	}
+	return true
+}
+
+// Over returns whether the game is over, either with no cards left to deal and
+// no match on the field, or with the time of a timed mode run out.
+func (t *TriGo) Over() bool {
+	return t.TimedOut() || t.cleared()
+}
+
+// Result summarizes a game.
+type Result struct {
+	Mode           Mode
+	Cleared        bool // whether all matches were found
+	Matches        int
+	Score          int
+	InvalidClaims  int
+	FalseNoMatches int
+	Hints          int
+	Leftover       int // cards left on the field
+	Elapsed        time.Duration
+}
+
+// Result returns a summary of the current game.
+func (t *TriGo) Result() Result {
+	leftover := 0
+	for _, c := range t.state.Field {
+		if c >= 0 {
+			leftover++
+		}
+	}
+	return Result{
+		Mode:           t.state.Mode,
+		Cleared:        t.cleared(),
+		Matches:        t.state.MatchesFound,
+		Score:          t.state.Score,
+		InvalidClaims:  t.state.InvalidClaims,
+		FalseNoMatches: t.state.FalseNoMatches,
+		Hints:          t.state.HintsUsed,
+		Leftover:       leftover,
+		Elapsed:        t.gameTime(),
+	}
+}
@@ -0,0 +1,107 @@
+package trigo
+
+import (
+	"testing"
+	"time"
+)
+
+// nonMatch returns the slots of three field cards that aren't a match.
+func nonMatch(t *TriGo) []int {
+	for i := 2; i < len(t.state.Field); i++ {
+		if slots := []int{0, 1, i}; !t.IsMatch(slots) {
+			return slots
+		}
+	}
+	return nil
+}
+
+// clockedGame returns a seeded standard game with standard scoring, timed by
+// clock.
+func clockedGame(clock *SimClock, seed int64) *TriGo {
+	t := NewStd()
+	t.SetTimeSource(clock.Now)
+	t.SetScoring(StdScoring()...)
+	t.Seed(seed)
+	t.Shuffle()
+	t.Deal()
+	return t
+}
+
+func TestScoring(t *testing.T) {
+	clock := &SimClock{}
+	tri := clockedGame(clock, 1)
+	score := 0
+	step := func(name string, wait time.Duration, act func() bool, want int) {
+		clock.Advance(wait)
+		if !act() {
+			t.Fatalf("%s failed", name)
+		}
+		score += want
+		if got := tri.Score(); got != score {
+			t.Fatalf("%s: score = %d, want %d", name, got, score)
+		}
+	}
+	claim := func() bool {
+		ok := tri.Claim(tri.FindMatch())
+		tri.Deal()
+		return ok
+	}
+	matchPoints := func() int {
+		return 10 + 5*tri.allDiff(tri.FindMatch())
+	}
+
+	// a quick match earns a point for each whole second under par
+	step("quick match", 10*time.Second+500*time.Millisecond, claim, matchPoints()+19)
+	step("slow match", 40*time.Second, claim, matchPoints())
+	step("match at par", 30*time.Second, claim, matchPoints())
+	step("invalid claim", time.Second, func() bool { return !tri.Claim(nonMatch(tri)) }, -5)
+	step("hint", time.Second, func() bool { return tri.Hint().Exists }, -3)
+	step("false no match", time.Second, func() bool { return !tri.DeclareNoMatch() }, -10)
+	step("match after a hint", 2*time.Second, claim, matchPoints()+25)
+
+	want := []time.Duration{
+		10*time.Second + 500*time.Millisecond, 40 * time.Second, 30 * time.Second, 5 * time.Second,
+	}
+	for i, d := range tri.MatchTimes() {
+		if d != want[i] {
+			t.Errorf("match %d took %v, want %v", i, d, want[i])
+		}
+	}
+
+	res := tri.Result()
+	if res.Score != score || res.Matches != 4 || res.InvalidClaims != 1 || res.Hints != 1 ||
+		res.FalseNoMatches != 1 || res.Cleared || res.Elapsed != 85*time.Second+500*time.Millisecond ||
+		res.Leftover != len(tri.state.Field)-blanks(tri) {
+		t.Errorf("result = %+v", res)
+	}
+	points := 0
+	for _, e := range tri.Log() {
+		points += e.Points
+	}
+	if points != score {
+		t.Errorf("logged points add up to %d, want %d", points, score)
+	}
+}
+
+// blanks returns the number of empty field slots.
+func blanks(t *TriGo) int {
+	n := 0
+	for _, c := range t.state.Field {
+		if c < 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDeclareNoMatch(t *testing.T) {
+	tri := fieldOf([]int{0, 0, 0, 0}, []int{0, 0, 0, 1}, []int{0, 0, 1, 0})
+	tri.SetScoring(StdScoring()...)
+	deck := len(tri.state.Deck)
+	if !tri.DeclareNoMatch() || tri.Score() != 0 {
+		t.Fatalf("right no-match declaration: score %d", tri.Score())
+	}
+	if n := len(tri.state.Field); n != 3+tri.state.FieldExpand || len(tri.state.Deck) != deck-tri.state.FieldExpand {
+		t.Errorf("field of %d cards after declaring no match", n)
+	}
+}
@@ -0,0 +1,195 @@
+// Package sim runs Monte Carlo simulations of trigo games, for tuning game
+// parameters and dealing policies.
+package sim
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ianremmler/trigo"
+)
+
+// Options describes a simulation.
+type Options struct {
+	Config  trigo.Config
+	Games   int
+	Seed    int64          // seed of the first game; each game after adds one
+	Workers int            // games played at once, or 0 for one per CPU
+	Skill   trigo.BotSkill // skill of the bot playing each game
+	// NewDealPolicy returns the dealing policy for a game, or nil to deal the
+	// deck in order.  It may be nil.
+	NewDealPolicy func() trigo.DealPolicy
+}
+
+// GameStats holds the statistics of one simulated game.
+type GameStats struct {
+	Seed       int64
+	Deals      int
+	Expansions int   // deals that had to expand the field
+	Available  []int // number of matches on the field after each deal
+	Matches    int
+	Leftover   int // cards left on the field at the end
+	Turns      int
+	Duration   time.Duration // simulated game time
+}
+
+// Summary holds the statistics of a simulation.
+type Summary struct {
+	Config        trigo.Config
+	Games         int
+	Deals         int
+	Expansions    int
+	ExpansionRate float64     // expansions per deal
+	Available     map[int]int // deals by number of matches available
+	Leftover      map[int]int // games by number of leftover cards
+	Length        map[int]int // games by number of matches found
+	MeanDuration  time.Duration
+	PerGame       []GameStats `json:"-"`
+}
+
+// ErrNegativeGames is returned by Run when asked for fewer than zero games.
+var ErrNegativeGames = errors.New("sim: number of games must not be negative")
+
+// Run plays the simulated games in parallel and summarizes them.  The results
+// depend only on the options, not on how the games are scheduled.
+func Run(opts Options) (*Summary, error) {
+	if err := opts.Config.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Games < 0 {
+		return nil, ErrNegativeGames
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	stats := make([]GameStats, opts.Games)
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				stats[i] = play(opts, opts.Seed+int64(i))
+			}
+		}()
+	}
+	for i := range stats {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return summarize(opts.Config, stats), nil
+}
+
+// play plays one game with a bot and returns its statistics.
+func play(opts Options, seed int64) GameStats {
+	t, _ := trigo.NewFromConfig(opts.Config)
+	clock := &trigo.SimClock{}
+	t.SetTimeSource(clock.Now)
+	if opts.NewDealPolicy != nil {
+		t.SetDealPolicy(opts.NewDealPolicy())
+	}
+	t.Seed(seed)
+	t.Shuffle()
+	t.Deal()
+
+	d := trigo.Driver{
+		Game:    t,
+		Players: []trigo.Player{trigo.NewBot(opts.Skill, seed)},
+		Clock:   clock,
+	}
+	rec := d.Run()
+
+	st := GameStats{
+		Seed:     seed,
+		Matches:  rec.Result.Matches,
+		Leftover: rec.Result.Leftover,
+		Turns:    len(rec.Turns),
+		Duration: rec.Result.Elapsed,
+	}
+	for _, e := range rec.Log {
+		if e.Kind != trigo.DealEvent {
+			continue
+		}
+		st.Deals++
+		if e.Expanded {
+			st.Expansions++
+		}
+		st.Available = append(st.Available, e.Available)
+	}
+	return st
+}
+
+func summarize(c trigo.Config, stats []GameStats) *Summary {
+	s := &Summary{
+		Config:    c,
+		Games:     len(stats),
+		Available: map[int]int{},
+		Leftover:  map[int]int{},
+		Length:    map[int]int{},
+		PerGame:   stats,
+	}
+	total := time.Duration(0)
+	for _, st := range stats {
+		s.Deals += st.Deals
+		s.Expansions += st.Expansions
+		for _, n := range st.Available {
+			s.Available[n]++
+		}
+		s.Leftover[st.Leftover]++
+		s.Length[st.Matches]++
+		total += st.Duration
+	}
+	if s.Deals > 0 {
+		s.ExpansionRate = float64(s.Expansions) / float64(s.Deals)
+	}
+	if s.Games > 0 {
+		s.MeanDuration = total / time.Duration(s.Games)
+	}
+	return s
+}
+
+// WriteJSON writes the summary as JSON.  Per-game statistics are left out.
+func (s *Summary) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteCSV writes the statistics of each game as CSV, with a header row.
+func (s *Summary) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"seed", "deals", "expansions", "mean_available",
+		"matches", "leftover", "turns", "duration_s"})
+	for _, st := range s.PerGame {
+		avail := 0.0
+		for _, n := range st.Available {
+			avail += float64(n)
+		}
+		if len(st.Available) > 0 {
+			avail /= float64(len(st.Available))
+		}
+		cw.Write([]string{
+			strconv.FormatInt(st.Seed, 10),
+			strconv.Itoa(st.Deals),
+			strconv.Itoa(st.Expansions),
+			strconv.FormatFloat(avail, 'f', 3, 64),
+			strconv.Itoa(st.Matches),
+			strconv.Itoa(st.Leftover),
+			strconv.Itoa(st.Turns),
+			strconv.FormatFloat(st.Duration.Seconds(), 'f', 3, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
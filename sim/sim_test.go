@@ -0,0 +1,33 @@
+package sim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ianremmler/trigo"
+)
+
+func TestRun(t *testing.T) {
+	opts := Options{Config: trigo.StdConfig(), Games: 8, Seed: 1, Skill: trigo.PerfectSkill()}
+	a, err := Run(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Games != opts.Games || len(a.PerGame) != opts.Games {
+		t.Fatalf("played %d games, want %d", a.Games, opts.Games)
+	}
+	opts.Workers = 1
+	b, err := Run(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Error("results depend on the number of workers")
+	}
+}
+
+func TestRunNegativeGames(t *testing.T) {
+	if _, err := Run(Options{Config: trigo.StdConfig(), Games: -1}); err != ErrNegativeGames {
+		t.Errorf("Run with -1 games: err = %v, want %v", err, ErrNegativeGames)
+	}
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ianremmler/trigo"
+)
+
+// ErrUnranked is returned for leaderboard entries that aren't standard games.
+var ErrUnranked = errors.New("store: only standard games in standard modes are ranked")
+
+// Entry is a game on a leaderboard, with the replay that proves its result.
+type Entry struct {
+	Player   string
+	Finished time.Time
+	Result   trigo.Result
+	Replay   trigo.Replay
+}
+
+// Verify checks that the game was a standard game in the standard mode of its
+// kind, then replays it and checks that it gives the entry's result.
+func (e Entry) Verify() error {
+	kind := e.Replay.Mode.Kind
+	if e.Replay.Config != trigo.StdConfig() || kind < 0 || int(kind) >= len(trigo.StdModes()) ||
+		e.Replay.Mode != trigo.StdModes()[kind] {
+		return ErrUnranked
+	}
+	if e.Replay.Mode != e.Result.Mode {
+		return trigo.ErrReplayMismatch
+	}
+	_, err := e.Replay.Verify(e.Result)
+	return err
+}
+
+func (s *Store) leaderboardFile(kind trigo.ModeKind) string {
+	return filepath.Join(s.dir, "leaderboards", kind.String()+".jsonl")
+}
+
+// AddEntry verifies an entry and adds it to the leaderboard of its mode.
+func (s *Store) AddEntry(e Entry) error {
+	if !validName(e.Player) {
+		return ErrBadName
+	}
+	if err := e.Verify(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.leaderboardFile(e.Result.Mode.Kind), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Leaderboard returns the best n entries of a mode, best first, or all of
+// them if n is 0.  Entries are not verified again; use Entry.Verify to check
+// entries that may have been tampered with.
+func (s *Store) Leaderboard(kind trigo.ModeKind, n int) ([]Entry, error) {
+	entries := []Entry{}
+	f, err := os.Open(s.leaderboardFile(kind))
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<24) // replays can make long lines
+	for sc.Scan() {
+		e := Entry{}
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Result.Better(entries[j].Result)
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries, nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ianremmler/trigo"
+)
+
+// playEntry plays a seeded game in mode to the end and returns its entry.
+func playEntry(c trigo.Config, mode trigo.Mode, seed int64) Entry {
+	clock := &trigo.SimClock{}
+	t, _ := trigo.NewFromConfig(c)
+	t.SetTimeSource(clock.Now)
+	t.SetScoring(trigo.StdScoring()...)
+	t.SetMode(mode)
+	t.Seed(seed)
+	t.Shuffle()
+	t.Deal()
+	d := trigo.Driver{Game: t, Players: []trigo.Player{trigo.NewBot(trigo.PerfectSkill(), seed)}, Clock: clock}
+	res := d.Run().Result
+	return Entry{Player: "ann", Finished: time.Now(), Result: res, Replay: t.Replay(seed)}
+}
+
+func TestAddEntry(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	std := trigo.StdModes()[trigo.Blitz]
+	if err := s.AddEntry(playEntry(trigo.StdConfig(), std, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	long := std
+	long.Limit *= 2
+	small := trigo.Config{NumAttrs: 3, NumAttrVals: 3, FieldSize: 9, FieldExpand: 3}
+	for name, e := range map[string]Entry{
+		"custom mode":   playEntry(trigo.StdConfig(), long, 2),
+		"custom config": playEntry(small, std, 3),
+	} {
+		if err := s.AddEntry(e); err != ErrUnranked {
+			t.Errorf("%s: err = %v, want %v", name, err, ErrUnranked)
+		}
+	}
+
+	forged := playEntry(trigo.StdConfig(), std, 4)
+	forged.Result.Matches++
+	if err := s.AddEntry(forged); err != trigo.ErrReplayMismatch {
+		t.Errorf("forged result: err = %v, want %v", err, trigo.ErrReplayMismatch)
+	}
+
+	entries, err := s.Leaderboard(trigo.Blitz, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("leaderboard has %d entries, want 1", len(entries))
+	}
+	if err := entries[0].Verify(); err != nil {
+		t.Errorf("stored entry doesn't verify: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+// Package store keeps player profiles and game results in local files, as
+// JSON, so they last across sessions.
+package store
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ianremmler/trigo"
+)
+
+// ErrBadName is returned for player names that can't be stored.
+var ErrBadName = errors.New("store: player names may only have letters, digits, '-' and '_'")
+
+// Profile holds what is known about a player.
+type Profile struct {
+	Name       string
+	Created    time.Time
+	Games      int
+	Best       map[string]Game // best game of each mode, by mode name
+	Streak     Streak
+	Weaknesses trigo.Weaknesses
+}
+
+// Streak counts the days in a row a player has finished a game.
+type Streak struct {
+	Current int
+	Longest int
+	LastDay string // the last day a game was finished, as YYYY-MM-DD
+}
+
+// Game is a finished game.
+type Game struct {
+	Finished time.Time
+	Result   trigo.Result
+}
+
+// Store keeps profiles and results in a directory.
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the directory of the current user's store.
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trigo"), nil
+}
+
+// Open returns the store in dir, creating the directory if needed.
+func Open(dir string) (*Store, error) {
+	for _, sub := range []string{"profiles", "results", "leaderboards"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &Store{dir: dir}, nil
+}
+
+// validName returns whether a player name can be stored.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Store) profileFile(name string) string {
+	return filepath.Join(s.dir, "profiles", name+".json")
+}
+
+func (s *Store) resultsFile(name string) string {
+	return filepath.Join(s.dir, "results", name+".jsonl")
+}
+
+// Profile returns the profile of the named player, or a new one if there is
+// none.  A new profile isn't saved until SaveProfile or AddResult.
+func (s *Store) Profile(name string) (*Profile, error) {
+	if !validName(name) {
+		return nil, ErrBadName
+	}
+	p := &Profile{}
+	data, err := ioutil.ReadFile(s.profileFile(name))
+	if err == nil {
+		if err := json.Unmarshal(data, p); err != nil {
+			return nil, err
+		}
+		if p.Best == nil {
+			p.Best = map[string]Game{}
+		}
+		return p, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return &Profile{Name: name, Created: time.Now(), Best: map[string]Game{}}, nil
+}
+
+// SaveProfile writes a profile.
+func (s *Store) SaveProfile(p *Profile) error {
+	if !validName(p.Name) {
+		return ErrBadName
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write a new file and move it into place, so a crash can't leave half a
+	// profile
+	name := s.profileFile(p.Name)
+	if err := ioutil.WriteFile(name+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(name+".tmp", name)
+}
+
+// Players returns the names of the players with profiles, in order.
+func (s *Store) Players() ([]string, error) {
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, "profiles"))
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, f := range files {
+		if name := strings.TrimSuffix(f.Name(), ".json"); name != f.Name() && validName(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// AddResult records a game the player finished at the given time, updating
+// their profile's best games and streak.  It returns whether the game is the
+// player's best in its mode.
+func (s *Store) AddResult(p *Profile, r trigo.Result, finished time.Time) (bool, error) {
+	if !validName(p.Name) {
+		return false, ErrBadName
+	}
+	g := Game{Finished: finished, Result: r}
+	data, err := json.Marshal(g)
+	if err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(s.resultsFile(p.Name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return false, err
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+
+	p.Games++
+	p.Streak.add(finished)
+	mode := r.Mode.Kind.String()
+	best, ok := p.Best[mode]
+	isBest := !ok || r.Better(best.Result)
+	if isBest {
+		p.Best[mode] = g
+	}
+	return isBest, s.SaveProfile(p)
+}
+
+// add counts a game finished at the given time.
+func (s *Streak) add(finished time.Time) {
+	day := finished.Format("2006-01-02")
+	switch s.LastDay {
+	case day:
+		return
+	case finished.AddDate(0, 0, -1).Format("2006-01-02"):
+		s.Current++
+	default:
+		s.Current = 1
+	}
+	s.LastDay = day
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+}
+
+// Results returns the games the player has finished, oldest first.
+func (s *Store) Results(name string) ([]Game, error) {
+	if !validName(name) {
+		return nil, ErrBadName
+	}
+	games := []Game{}
+	f, err := os.Open(s.resultsFile(name))
+	if os.IsNotExist(err) {
+		return games, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		g := Game{}
+		if err := json.Unmarshal(sc.Bytes(), &g); err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+	return games, sc.Err()
+}
+
+// ExportJSON writes the player's profile and results as indented JSON.
+func (s *Store) ExportJSON(w io.Writer, name string) error {
+	p, err := s.Profile(name)
+	if err != nil {
+		return err
+	}
+	games, err := s.Results(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Profile *Profile
+		Results []Game
+	}{p, games})
+}
+
+// ExportCSV writes the player's results as CSV, with a header row.
+func (s *Store) ExportCSV(w io.Writer, name string) error {
+	games, err := s.Results(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"finished", "mode", "cleared", "matches", "score",
+		"invalid_claims", "false_no_matches", "hints", "leftover", "elapsed_s"})
+	for _, g := range games {
+		r := g.Result
+		cw.Write([]string{
+			g.Finished.Format(time.RFC3339),
+			r.Mode.Kind.String(),
+			strconv.FormatBool(r.Cleared),
+			strconv.Itoa(r.Matches),
+			strconv.Itoa(r.Score),
+			strconv.Itoa(r.InvalidClaims),
+			strconv.Itoa(r.FalseNoMatches),
+			strconv.Itoa(r.Hints),
+			strconv.Itoa(r.Leftover),
+			strconv.FormatFloat(r.Elapsed.Seconds(), 'f', 3, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
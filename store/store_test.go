@@ -0,0 +1,197 @@
+package store
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ianremmler/trigo"
+)
+
+func TestProfile(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Profile("a b"); err != ErrBadName {
+		t.Errorf("Profile(\"a b\") = %v, want %v", err, ErrBadName)
+	}
+	if err := s.SaveProfile(&Profile{Name: "../x"}); err != ErrBadName {
+		t.Errorf("SaveProfile(\"../x\") = %v, want %v", err, ErrBadName)
+	}
+
+	p, err := s.Profile("ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "ann" || p.Games != 0 || p.Best == nil {
+		t.Errorf("new profile %+v", p)
+	}
+	if names, _ := s.Players(); len(names) != 0 {
+		t.Errorf("players %v before any profile was saved", names)
+	}
+
+	p.Games = 3
+	p.Weaknesses.Patterns = []trigo.PatternStats{{Attr: 1, AllDiff: true, Available: 4, Taken: 2, Time: time.Second}}
+	if err := s.SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveProfile(&Profile{Name: "Bob_2"}); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(s.dir, "profiles", "notes.txt"), nil, 0644)
+	q, err := s.Profile("ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Games != 3 || !q.Created.Equal(p.Created) || !reflect.DeepEqual(q.Weaknesses, p.Weaknesses) {
+		t.Errorf("loaded %+v, want %+v", q, p)
+	}
+	if q, _ := s.Profile("Bob_2"); q.Best == nil {
+		t.Error("profile saved without best games has a nil map")
+	}
+	if names, _ := s.Players(); !reflect.DeepEqual(names, []string{"Bob_2", "ann"}) {
+		t.Errorf("players %v, want [Bob_2 ann]", names)
+	}
+}
+
+// result returns a result of the standard mode of the given kind.
+func result(kind trigo.ModeKind, matches int, elapsed time.Duration) trigo.Result {
+	return trigo.Result{Mode: trigo.StdModes()[kind], Matches: matches, Score: 10 * matches, Elapsed: elapsed}
+}
+
+func TestAddResult(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := s.Profile("ann")
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	games := []struct {
+		result trigo.Result
+		best   bool
+	}{
+		{result(trigo.Blitz, 5, 90*time.Second), true},
+		{result(trigo.Blitz, 3, 90*time.Second), false},
+		{result(trigo.TimeAttack, 10, 200*time.Second), true},
+		{result(trigo.Blitz, 7, 90*time.Second), true},
+	}
+	for i, g := range games {
+		best, err := s.AddResult(p, g.result, day.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if best != g.best {
+			t.Errorf("game %d: best = %v, want %v", i, best, g.best)
+		}
+	}
+	if p.Games != len(games) {
+		t.Errorf("%d games, want %d", p.Games, len(games))
+	}
+
+	q, _ := s.Profile("ann")
+	if !reflect.DeepEqual(q.Best[trigo.Blitz.String()].Result, games[3].result) ||
+		!reflect.DeepEqual(q.Best[trigo.TimeAttack.String()].Result, games[2].result) {
+		t.Errorf("saved best games %+v", q.Best)
+	}
+	results, err := s.Results("ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(games) {
+		t.Fatalf("%d results, want %d", len(results), len(games))
+	}
+	for i, g := range results {
+		if !reflect.DeepEqual(g.Result, games[i].result) || !g.Finished.Equal(day.Add(time.Duration(i)*time.Hour)) {
+			t.Errorf("result %d: %+v", i, g)
+		}
+	}
+	if results, err := s.Results("bob"); err != nil || len(results) != 0 {
+		t.Errorf("results of a new player %v, %v", results, err)
+	}
+}
+
+func TestStreak(t *testing.T) {
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	s := Streak{}
+	for _, tc := range []struct {
+		name             string
+		at               time.Time
+		current, longest int
+	}{
+		{"first game", at(2, 27, 23), 1, 1},
+		{"same day", at(2, 27, 1), 1, 1},
+		{"next day", at(2, 28, 23), 2, 2},
+		{"across the month", at(3, 1, 1), 3, 3},
+		{"same day again", at(3, 1, 23), 3, 3},
+		{"after a gap", at(3, 3, 12), 1, 3},
+		{"next day after the gap", at(3, 4, 12), 2, 3},
+	} {
+		s.add(tc.at)
+		if s.Current != tc.current || s.Longest != tc.longest {
+			t.Errorf("%s: streak %d, longest %d, want %d and %d", tc.name, s.Current, s.Longest, tc.current, tc.longest)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := s.Profile("ann")
+	finished := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	games := []trigo.Result{result(trigo.Blitz, 5, 90*time.Second), result(trigo.Survival, 8, 1500*time.Millisecond)}
+	for _, r := range games {
+		if _, err := s.AddResult(p, r, finished); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := s.ExportCSV(buf, "ann"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"finished", "mode", "cleared", "matches", "score", "invalid_claims", "false_no_matches", "hints", "leftover", "elapsed_s"},
+		{"2026-03-01T12:00:00Z", "blitz", "false", "5", "50", "0", "0", "0", "0", "90.000"},
+		{"2026-03-01T12:00:00Z", "survival", "false", "8", "80", "0", "0", "0", "0", "1.500"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("CSV %v, want %v", rows, want)
+	}
+
+	buf.Reset()
+	if err := s.ExportJSON(buf, "ann"); err != nil {
+		t.Fatal(err)
+	}
+	export := struct {
+		Profile Profile
+		Results []Game
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &export); err != nil {
+		t.Fatal(err)
+	}
+	if export.Profile.Name != "ann" || export.Profile.Games != len(games) || len(export.Results) != len(games) {
+		t.Fatalf("JSON export %+v", export)
+	}
+	for i, g := range export.Results {
+		if !reflect.DeepEqual(g.Result, games[i]) {
+			t.Errorf("exported result %d: %+v, want %+v", i, g.Result, games[i])
+		}
+	}
+	if err := s.ExportCSV(buf, "a b"); err != ErrBadName {
+		t.Errorf("ExportCSV(\"a b\") = %v, want %v", err, ErrBadName)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"encoding/gob"
 	"math"
+	"math/bits"
 	"math/rand"
+	"time"
 )
 
 // Card represents a playing card with attributes.
@@ -24,16 +26,47 @@ type gameState struct {
 	Deck         []int
 	Field        []int
 	MatchesFound int
+	HintLevel    int
+	HintSlots    []int
+	HintsUsed    int
+
+	Score          int
+	InvalidClaims  int
+	FalseNoMatches int
+
+	Elapsed     time.Duration // clock time up to the last pause or checkpoint
+	Paused      bool
+	LastMatchAt time.Duration
+	MatchTimes  []time.Duration
+
+	Mode Mode
+	Log  []Event
 }
 
 // TriGo represents an instance of a game and its state.
 type TriGo struct {
 	state *gameState
+
+	// The fields below are derived from state by pack and are not saved.
+
+	// attrBits holds each card's attribute values, words words per card.
+	// Each attribute takes NumAttrVals bits, with the bit for its value set.
+	attrBits []uint64
+	words    int
+	perWord  int      // attributes per word
+	inField  []uint64 // bitset of the cards on the field
+
+	policies   []ScorePolicy
+	dealPolicy DealPolicy
+	rng        *rand.Rand
+	timeSource func() time.Time
+	resumed    time.Time // when the clock last started running
+	counting   bool      // whether resumed is set, as the clock starts when read
 }
 
 // NewStd returns an instance of a standard game.
 func NewStd() *TriGo {
-	return New(4, 3, 12, 3)
+	return newGame(StdConfig())
 }
 
 // NewFromSavedState returns a game instance initialized to the given state.
@@ -44,34 +77,92 @@ func NewFromSavedState(state []byte) *TriGo {
 	if dec.Decode(&t.state) != nil {
 		return nil
 	}
+	t.pack()
 	return t
 }
 
-// New returns an instance of a custom game.
+// New returns an instance of a custom game.  The parameters are not checked;
+// use NewFromConfig for that.
 func New(numAttrs, numAttrVals, fieldSize, fieldExpand int) *TriGo {
-	numCards := 1
-	for i := 0; i < numAttrs; i++ {
-		numCards *= numAttrVals
-	}
-	t := &TriGo{}
-	t.state = &gameState{
+	return newGame(Config{
 		NumAttrs:    numAttrs,
 		NumAttrVals: numAttrVals,
 		FieldSize:   fieldSize,
 		FieldExpand: fieldExpand,
+	})
+}
+
+// NewFromConfig returns an instance of a custom game, or an error if the
+// configuration is not valid.
+func NewFromConfig(c Config) (*TriGo, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return newGame(c), nil
+}
+
+func newGame(c Config) *TriGo {
+	numCards := 1
+	for i := 0; i < c.NumAttrs; i++ {
+		numCards *= c.NumAttrVals
+	}
+	t := &TriGo{}
+	t.state = &gameState{
+		NumAttrs:    c.NumAttrs,
+		NumAttrVals: c.NumAttrVals,
+		FieldSize:   c.FieldSize,
+		FieldExpand: c.FieldExpand,
 		Cards:       make([]Card, numCards),
 		Deck:        make([]int, numCards),
-		Field:       make([]int, fieldSize),
+		Field:       make([]int, c.FieldSize),
 	}
 	for i := range t.state.Cards {
-		t.state.Cards[i].Attr = make([]int, numAttrs)
+		t.state.Cards[i].Attr = make([]int, c.NumAttrs)
 	}
 	t.genCards()
+	t.pack()
 	t.Shuffle()
 	return t
 }
 
+// Clone returns a copy of the game that can be played independently.  The
+// deck, field, hint slots and field bitset are copied.  The card table never
+// changes once generated, and the match times and log are only appended to, so
+// those are shared until either game appends.  Score and deal policies are
+// shared with the copy.  The random source set by Seed is not, so the copy
+// shuffles like an unseeded game.
+func (t *TriGo) Clone() *TriGo {
+	state := *t.state
+	state.Deck = append([]int(nil), t.state.Deck...)
+	state.Field = append([]int(nil), t.state.Field...)
+	state.HintSlots = append([]int(nil), t.state.HintSlots...)
+	// these are only appended to, so limiting capacity makes appends copy
+	state.MatchTimes = t.state.MatchTimes[:len(t.state.MatchTimes):len(t.state.MatchTimes)]
+	state.Log = t.state.Log[:len(t.state.Log):len(t.state.Log)]
+	c := *t
+	c.state = &state
+	c.inField = append([]uint64(nil), t.inField...)
+	c.rng = nil
+	return &c
+}
+
+// Config returns the configuration of the game.
+func (t *TriGo) Config() Config {
+	return Config{
+		NumAttrs:    t.state.NumAttrs,
+		NumAttrVals: t.state.NumAttrVals,
+		FieldSize:   t.state.FieldSize,
+		FieldExpand: t.state.FieldExpand,
+	}
+}
+
+// State returns the game state serialized with gob.  A running game clock
+// carries on from the saved time in the restored game.
 func (t *TriGo) State() ([]byte, error) {
+	if !t.state.Paused {
+		t.state.Elapsed = t.Elapsed()
+		t.resumed = t.now()
+	}
 	buf := &bytes.Buffer{}
 	enc := gob.NewEncoder(buf)
 	if err := enc.Encode(t.state); err != nil {
@@ -90,6 +181,86 @@ func (t *TriGo) genCards() {
 	}
 }
 
+// pack builds the bitset representation of the cards and the field.
+func (t *TriGo) pack() {
+	t.perWord = 1
+	if t.state.NumAttrVals > 0 {
+		t.perWord = 64 / t.state.NumAttrVals
+	}
+	if t.perWord < 1 {
+		t.perWord = 1
+	}
+	t.words = (t.state.NumAttrs + t.perWord - 1) / t.perWord
+	t.attrBits = make([]uint64, len(t.state.Cards)*t.words)
+	for i, card := range t.state.Cards {
+		for j, val := range card.Attr {
+			shift := uint(j%t.perWord*t.state.NumAttrVals + val)
+			t.attrBits[i*t.words+j/t.perWord] |= 1 << shift
+		}
+	}
+	t.inField = make([]uint64, (len(t.state.Cards)+63)/64)
+	for _, c := range t.state.Field {
+		if c >= 0 && c < len(t.state.Cards) {
+			t.inField[c/64] |= 1 << uint(c%64)
+		}
+	}
+}
+
+// isInField returns whether card c is on the field.
+func (t *TriGo) isInField(c int) bool {
+	return t.inField[c/64]&(1<<uint(c%64)) != 0
+}
+
+// attrsInWord returns the number of attributes packed in word w of a card.
+func (t *TriGo) attrsInWord(w int) int {
+	if n := t.state.NumAttrs - w*t.perWord; n < t.perWord {
+		return n
+	}
+	return t.perWord
+}
+
+// valsMatch returns whether the combined attribute bits m of word w are all
+// the same or all different for every attribute.
+func (t *TriGo) valsMatch(m uint64, w int) bool {
+	numVals := uint(t.state.NumAttrVals)
+	full := uint64(1)<<numVals - 1
+	for i := 0; i < t.attrsInWord(w); i++ {
+		vals := m >> (uint(i) * numVals) & full
+		if vals&(vals-1) != 0 && vals != full {
+			return false
+		}
+	}
+	return true
+}
+
+// complete returns the card that completes a match with the given cards, which
+// must number one less than the match size.  It only works for match sizes of
+// at least three, as smaller matches are not uniquely determined.
+func (t *TriGo) complete(cards []int) (int, bool) {
+	numVals := uint(t.state.NumAttrVals)
+	full := uint64(1)<<numVals - 1
+	id, place := 0, 1
+	for w := 0; w < t.words; w++ {
+		m := uint64(0)
+		for _, c := range cards {
+			m |= t.attrBits[c*t.words+w]
+		}
+		for i := 0; i < t.attrsInWord(w); i++ {
+			vals := m >> (uint(i) * numVals) & full
+			switch bits.OnesCount64(vals) {
+			case 1: // all same so far
+			case len(cards): // all different so far, take the missing value
+				vals = full &^ vals
+			default:
+				return -1, false
+			}
+			id += bits.TrailingZeros64(vals) * place
+			place *= int(numVals)
+		}
+	}
+	return id, true
+}
+
 // DeckSize returns the number of cards currently in the deck.
 func (t *TriGo) DeckSize() int {
 	return len(t.state.Deck)
@@ -112,14 +283,29 @@ func (t *TriGo) FieldCard(i int) Card {
 	return t.Card(t.state.Field[i])
 }
 
-// Shuffle refills and shuffles the deck, and clears the field.
+// Shuffle refills and shuffles the deck, clears the field, and starts a new
+// game, resetting the score and the game clock.
 func (t *TriGo) Shuffle() {
-	t.state.Deck = rand.Perm(len(t.state.Cards))
+	if t.rng != nil {
+		t.state.Deck = t.rng.Perm(len(t.state.Cards))
+	} else {
+		t.state.Deck = rand.Perm(len(t.state.Cards))
+	}
 	t.state.Field = make([]int, t.state.FieldSize)
 	for i := range t.state.Field {
 		t.state.Field[i] = -1
 	}
+	for i := range t.inField {
+		t.inField[i] = 0
+	}
 	t.state.MatchesFound = 0
+	t.state.HintsUsed = 0
+	t.state.Score = 0
+	t.state.InvalidClaims = 0
+	t.state.FalseNoMatches = 0
+	t.state.Log = nil
+	t.resetHint()
+	t.startClock()
 }
 
 // Remove removes a match from the field.
@@ -127,10 +313,14 @@ func (t *TriGo) Shuffle() {
 func (t *TriGo) Remove(match []int) {
 	for _, i := range match {
 		if i >= 0 && i < len(t.state.Field) {
+			if c := t.state.Field[i]; c >= 0 {
+				t.inField[c/64] &^= 1 << uint(c%64)
+			}
 			t.state.Field[i] = -1
 		}
 	}
 	t.state.MatchesFound++
+	t.resetHint()
 }
 
 // MatchesFound returns the number of matches found in the current game
@@ -149,20 +339,27 @@ func (t *TriGo) expandField() {
 
 // tidyField moves cards to empty slots and shrinks field if possible.
 func (t *TriGo) tidyField() {
-	numExtra := len(t.state.Field) - t.state.FieldSize
-	for i, e := range t.state.Field[t.state.FieldSize:] {
+	main := t.state.Field[:t.state.FieldSize]
+	extra := t.state.Field[t.state.FieldSize:]
+	numExtra := 0
+	for i, e := range extra {
 		if e < 0 {
-			numExtra--
 			continue
 		}
-		for j, c := range t.state.Field[:t.state.FieldSize] {
+		extra[i] = -1
+		moved := false
+		for j, c := range main {
 			if c < 0 {
-				t.state.Field[j] = e
-				t.state.Field[t.state.FieldSize+i] = -1
-				numExtra--
+				main[j] = e
+				moved = true
 				break
 			}
 		}
+		if !moved {
+			// keep extra cards together so shrinking can't drop any
+			extra[numExtra] = e
+			numExtra++
+		}
 	}
 	expand := float64(t.state.FieldExpand)
 	numExtra = int(math.Ceil(float64(numExtra)/expand) * expand)
@@ -176,8 +373,15 @@ func (t *TriGo) addCards() {
 			if len(t.state.Deck) == 0 {
 				break
 			}
-			t.state.Field[i] = t.state.Deck[0]
-			t.state.Deck = t.state.Deck[1:]
+			j := t.pick()
+			c = t.state.Deck[j]
+			t.state.Field[i] = c
+			if j == 0 {
+				t.state.Deck = t.state.Deck[1:]
+			} else {
+				t.state.Deck = append(t.state.Deck[:j], t.state.Deck[j+1:]...)
+			}
+			t.inField[c/64] |= 1 << uint(c%64)
 		}
 	}
 }
@@ -185,12 +389,16 @@ func (t *TriGo) addCards() {
 // Deal deals new cards to the field, expanding the field if necessary until at
 // least one match is available.
 func (t *TriGo) Deal() {
+	t.resetHint()
 	t.tidyField()
 	t.addCards()
+	expanded := false
 	if t.FieldMatches() == 0 && len(t.state.Deck) > 0 {
 		t.expandField()
 		t.addCards()
+		expanded = true
 	}
+	t.recordDeal(expanded)
 }
 
 // Field returns a slice of card indices representing the current field.
@@ -208,53 +416,90 @@ func (t *TriGo) Field() []Card {
 
 // IsMatch returns whether a given match candidate is valid
 func (t *TriGo) IsMatch(candidate []int) bool {
-	if len(candidate) != t.state.NumAttrVals {
+	if t.checkCandidate(candidate) != nil {
 		return false
 	}
-	attrCheck := make([]int, t.state.NumAttrs)
-	for _, f := range candidate {
-		if f < 0 || f >= len(t.state.Field) {
-			return false
-		}
-		c := t.state.Field[f]
-		if c < 0 || c >= len(t.state.Cards) {
-			return false
-		}
-		card := &t.state.Cards[c]
-		for i, val := range card.Attr {
-			attrCheck[i] |= 1 << uint(val)
+	for w := 0; w < t.words; w++ {
+		m := uint64(0)
+		for _, f := range candidate {
+			m |= t.attrBits[t.state.Field[f]*t.words+w]
 		}
-	}
-	for _, attr := range attrCheck {
-		allSame := (attr != 0) && (attr&(attr-1) == 0)
-		allDiff := (attr == 1<<uint(t.state.NumAttrVals)-1)
-		if !allSame && !allDiff {
+		if !t.valsMatch(m, w) {
 			return false
 		}
 	}
 	return true
 }
 
-// FieldMatches returns the number of matches in the field.
-func (t *TriGo) FieldMatches() int {
-	numMatches := 0
-	candidate := make([]int, t.state.NumAttrVals)
-
-	var recurse func(int, int)
-	recurse = func(i, n int) {
-		for j := n; j < len(t.state.Field); j++ {
-			candidate[i] = j
-			if i == t.state.NumAttrVals-1 {
-				if t.IsMatch(candidate) {
-					numMatches++
+// eachMatch calls fn with the ascending field slots of each match in the field
+// until fn returns false.  The slice passed to fn is reused between calls.
+func (t *TriGo) eachMatch(fn func(slots []int) bool) {
+	field := t.state.Field
+	size := t.state.NumAttrVals
+	slots := make([]int, size)
+
+	if size < 3 {
+		// no unique completion, so check every combination
+		var recurse func(int, int) bool
+		recurse = func(i, n int) bool {
+			for j := n; j < len(field); j++ {
+				slots[i] = j
+				if i < size-1 {
+					if !recurse(i+1, j+1) {
+						return false
+					}
+				} else if t.IsMatch(slots) && !fn(slots) {
+					return false
 				}
-			} else {
-				recurse(i+1, j+1)
 			}
+			return true
+		}
+		if size > 0 {
+			recurse(0, 0)
 		}
 		return
 	}
+
+	// choose all but one card, and look for the one that completes the match
+	cards := make([]int, size-1)
+	var recurse func(int, int) bool
+	recurse = func(i, n int) bool {
+		for j := n; j < len(field); j++ {
+			if field[j] < 0 {
+				continue
+			}
+			slots[i], cards[i] = j, field[j]
+			if i < size-2 {
+				if !recurse(i+1, j+1) {
+					return false
+				}
+				continue
+			}
+			c, ok := t.complete(cards)
+			if !ok || !t.isInField(c) {
+				continue
+			}
+			for k := j + 1; k < len(field); k++ {
+				if field[k] == c {
+					slots[size-1] = k
+					if !fn(slots) {
+						return false
+					}
+					break
+				}
+			}
+		}
+		return true
+	}
 	recurse(0, 0)
+}
 
+// FieldMatches returns the number of matches in the field.
+func (t *TriGo) FieldMatches() int {
+	numMatches := 0
+	t.eachMatch(func([]int) bool {
+		numMatches++
+		return true
+	})
 	return numMatches
 }
@@ -0,0 +1,220 @@
+package trigo
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTidyFieldKeepsExtraCards(t *testing.T) {
+	tri := NewStd()
+	tri.Shuffle()
+	size, expand := tri.state.FieldSize, tri.state.FieldExpand
+	field := make([]int, size+2*expand)
+	for i := range field {
+		field[i] = i
+	}
+	for i := size; i < size+expand; i++ {
+		field[i] = -1
+	}
+	tri.state.Field = field
+	want := size + expand
+
+	tri.tidyField()
+	if got := len(tri.state.Field); got != want {
+		t.Fatalf("field length = %d, want %d", got, want)
+	}
+	seen := map[int]bool{}
+	for _, c := range tri.state.Field {
+		if c >= 0 {
+			seen[c] = true
+		}
+	}
+	if len(seen) != want {
+		t.Errorf("field holds %d cards after tidying, want %d", len(seen), want)
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	tri := NewStd()
+	tri.Deal()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tri.Clone()
+	}
+}
+
+func BenchmarkStateRestore(b *testing.B) {
+	tri := NewStd()
+	tri.Deal()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state, err := tri.State()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if NewFromSavedState(state) == nil {
+			b.Fatal("restore failed")
+		}
+	}
+}
+
+// bruteIsMatch checks the match rule on the card attributes directly, as
+// IsMatch did before packing.
+func bruteIsMatch(t *TriGo, cards []int) bool {
+	attrCheck := make([]int, t.state.NumAttrs)
+	for _, c := range cards {
+		for i, val := range t.state.Cards[c].Attr {
+			attrCheck[i] |= 1 << uint(val)
+		}
+	}
+	for _, attr := range attrCheck {
+		allSame := attr&(attr-1) == 0
+		allDiff := attr == 1<<uint(t.state.NumAttrVals)-1
+		if !allSame && !allDiff {
+			return false
+		}
+	}
+	return true
+}
+
+// bruteMatches returns the ascending slots of every match in the field by
+// checking every combination, as FieldMatches did before packing.
+func bruteMatches(t *TriGo) [][]int {
+	var matches [][]int
+	size := t.state.NumAttrVals
+	slots := make([]int, size)
+	cards := make([]int, size)
+	var recurse func(int, int)
+	recurse = func(i, n int) {
+		for j := n; j < len(t.state.Field); j++ {
+			if t.state.Field[j] < 0 {
+				continue
+			}
+			slots[i], cards[i] = j, t.state.Field[j]
+			if i < size-1 {
+				recurse(i+1, j+1)
+			} else if bruteIsMatch(t, cards) {
+				matches = append(matches, append([]int(nil), slots...))
+			}
+		}
+	}
+	recurse(0, 0)
+	return matches
+}
+
+// randomField deals size random cards, leaving some slots blank.
+func randomField(t *TriGo, rng *rand.Rand, size int) {
+	t.state.Field = rng.Perm(len(t.state.Cards))[:size]
+	for i := range t.state.Field {
+		if rng.Intn(8) == 0 {
+			t.state.Field[i] = -1
+		}
+	}
+	t.pack()
+}
+
+func TestMatchRule(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, c := range []struct{ attrs, vals, field int }{
+		{4, 3, 15}, {3, 4, 16}, {2, 5, 15}, {5, 3, 18}, {2, 7, 14},
+	} {
+		tri := New(c.attrs, c.vals, c.field, c.vals)
+		for n := 0; n < 50; n++ {
+			randomField(tri, rng, c.field)
+			want := bruteMatches(tri)
+			var got [][]int
+			tri.eachMatch(func(slots []int) bool {
+				if !tri.IsMatch(slots) {
+					t.Errorf("%d/%d: eachMatch gave non-match %v", c.attrs, c.vals, slots)
+				}
+				got = append(got, append([]int(nil), slots...))
+				return true
+			})
+			sortSlots(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%d/%d: field %v: matches %v, want %v",
+					c.attrs, c.vals, tri.state.Field, got, want)
+			}
+			if n := tri.FieldMatches(); n != len(want) {
+				t.Fatalf("%d/%d: FieldMatches = %d, want %d", c.attrs, c.vals, n, len(want))
+			}
+		}
+
+		// every set of all but one distinct cards has exactly one completion
+		for n := 0; n < 200; n++ {
+			cards := rng.Perm(len(tri.state.Cards))[:c.vals-1]
+			want, wantOK := -1, false
+			for card := range tri.state.Cards {
+				if bruteIsMatch(tri, append(cards, card)) {
+					want, wantOK = card, true
+					break
+				}
+			}
+			if got, ok := tri.complete(cards); got != want || ok != wantOK {
+				t.Fatalf("%d/%d: complete(%v) = %d, %v, want %d, %v",
+					c.attrs, c.vals, cards, got, ok, want, wantOK)
+			}
+		}
+	}
+}
+
+// sortSlots sorts matches lexicographically, the order bruteMatches finds them.
+func sortSlots(matches [][]int) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
+
+func benchmarkField() *TriGo {
+	tri := New(4, 3, 12, 3)
+	randomField(tri, rand.New(rand.NewSource(1)), 12)
+	return tri
+}
+
+func BenchmarkFieldMatches(b *testing.B) {
+	tri := benchmarkField()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tri.FieldMatches()
+	}
+}
+
+func BenchmarkFieldMatchesBrute(b *testing.B) {
+	tri := benchmarkField()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bruteMatches(tri)
+	}
+}
+
+func TestCloneRandomSource(t *testing.T) {
+	tri, want := NewStd(), NewStd()
+	tri.Seed(1)
+	want.Seed(1)
+	tri.Clone().Shuffle()
+	tri.Shuffle()
+	want.Shuffle()
+	if !reflect.DeepEqual(tri.state.Deck, want.state.Deck) {
+		t.Error("shuffling a clone changed the original's random source")
+	}
+}
+
+func TestNewUnchecked(t *testing.T) {
+	// New doesn't check its arguments, but shouldn't panic on degenerate ones
+	for _, vals := range []int{0, 1, 2} {
+		tri := New(4, vals, 12, 3)
+		tri.Deal()
+		tri.FieldMatches()
+		if _, err := tri.State(); err != nil {
+			t.Errorf("%d values: %v", vals, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package trigo
+
+import "fmt"
+
+// Lesson is a step of a tutorial: an explanation, then an exercise to find
+// every match in a small field of standard cards.
+type Lesson struct {
+	Title string
+	Text  string
+	// Cards lists the attribute values of each card of the exercise, in the
+	// order number, color, shape, fill.
+	Cards [][]int
+}
+
+// Puzzle returns the exercise of the lesson.
+func (l Lesson) Puzzle() (*Puzzle, error) {
+	c := StdConfig()
+	t := newGame(c)
+	ids := make([]int, len(l.Cards))
+	for i, attrs := range l.Cards {
+		if len(attrs) != c.NumAttrs {
+			return nil, fmt.Errorf("trigo: lesson card %d has %d attributes, not %d",
+				i, len(attrs), c.NumAttrs)
+		}
+		for _, v := range attrs {
+			if v < 0 || v >= c.NumAttrVals {
+				return nil, fmt.Errorf("trigo: lesson card %d has invalid value %d", i, v)
+			}
+		}
+		ids[i] = t.cardID(attrs)
+	}
+	return NewPuzzle(c, ids)
+}
+
+// StdTutorial returns the lessons of the standard tutorial, which introduce
+// one attribute at a time.
+func StdTutorial() []Lesson {
+	return []Lesson{
+		{
+			Title: "Number",
+			Text: "Each card shows one, two or three symbols.  Three cards make a match " +
+				"when, for each attribute, they are all the same or all different.  " +
+				"These cards differ only in number: one, two and three are all " +
+				"different, so they make a match.  Select them.",
+			Cards: [][]int{
+				{0, 0, 0, 0}, {1, 0, 0, 0}, {2, 0, 0, 0},
+			},
+		},
+		{
+			Title: "Color",
+			Text: "Now the cards differ in color too.  A match needs the colors all the " +
+				"same or all different, as well as the numbers.  Two of one color and " +
+				"one of another is not a match.",
+			Cards: [][]int{
+				{0, 0, 0, 0}, {1, 1, 0, 0}, {2, 2, 0, 0},
+				{0, 1, 0, 0}, {1, 0, 0, 0}, {2, 1, 0, 0},
+			},
+		},
+		{
+			Title: "Shape",
+			Text: "Shape is the third attribute.  Check each attribute in turn: number, " +
+				"color and shape must each be all the same or all different.  A match " +
+				"can mix the two, such as the same color with different shapes.",
+			Cards: [][]int{
+				{0, 0, 0, 0}, {1, 0, 1, 0}, {2, 0, 2, 0},
+				{0, 1, 2, 0}, {1, 2, 0, 0}, {2, 1, 1, 0},
+				{0, 2, 1, 0}, {2, 2, 2, 0}, {1, 1, 0, 0},
+			},
+		},
+		{
+			Title: "Fill",
+			Text: "The last attribute is fill: outline, striped or solid.  This is a " +
+				"whole field, as in the game.  Look for cards that share some " +
+				"attributes, then check whether the rest are all different.",
+			Cards: [][]int{
+				{0, 0, 0, 0}, {1, 1, 1, 1}, {2, 2, 2, 2},
+				{0, 1, 2, 0}, {1, 2, 0, 2}, {2, 0, 1, 1},
+				{1, 0, 0, 2}, {0, 2, 1, 1}, {2, 1, 2, 0},
+				{2, 0, 0, 1}, {1, 2, 2, 0}, {0, 1, 0, 1},
+			},
+		},
+	}
+}
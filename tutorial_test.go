@@ -0,0 +1,35 @@
+package trigo
+
+import "testing"
+
+func TestStdTutorial(t *testing.T) {
+	want := []int{1, 3, 6, 6}
+	lessons := StdTutorial()
+	if len(lessons) != len(want) {
+		t.Fatalf("%d lessons, want %d", len(lessons), len(want))
+	}
+	for i, l := range lessons {
+		p, err := l.Puzzle()
+		if err != nil {
+			t.Fatalf("%s: %v", l.Title, err)
+		}
+		if n := p.NumMatches(); n != want[i] {
+			t.Errorf("%s: %d matches, want %d", l.Title, n, want[i])
+		}
+		if n := len(bruteMatches(p.t)); n != p.NumMatches() {
+			t.Errorf("%s: %d matches by brute force, puzzle has %d", l.Title, n, p.NumMatches())
+		}
+	}
+}
+
+func TestLessonPuzzle(t *testing.T) {
+	for _, cards := range [][][]int{
+		{{0, 0, 0}},
+		{{0, 0, 0, 3}},
+		{{0, 0, 0, -1}},
+	} {
+		if _, err := (Lesson{Cards: cards}).Puzzle(); err == nil {
+			t.Errorf("lesson cards %v accepted", cards)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package trigo
+
+// weaknessPrior is the number of average matches assumed for each pattern, so
+// patterns seen only a few times aren't judged too harshly.
+const weaknessPrior = 5
+
+// Weaknesses tracks how a player does with each match pattern across games.
+type Weaknesses struct {
+	// Patterns sums the pattern statistics of every game reported, indexed
+	// as in Report.
+	Patterns []PatternStats
+}
+
+// Add adds the pattern statistics of a game.
+func (w *Weaknesses) Add(r Report) {
+	if len(w.Patterns) != len(r.Patterns) {
+		w.Patterns = make([]PatternStats, len(r.Patterns))
+		for i, p := range r.Patterns {
+			w.Patterns[i] = PatternStats{Attr: p.Attr, AllDiff: p.AllDiff}
+		}
+	}
+	for i, p := range r.Patterns {
+		w.Patterns[i].Available += p.Available
+		w.Patterns[i].Taken += p.Taken
+		w.Patterns[i].Time += p.Time
+	}
+}
+
+// Weights rates how weak the player is at each pattern, indexed as in Report.
+// A weight of 1 is average, and higher weights mean the player takes matches
+// of the pattern less often than others, or more slowly.
+func (w *Weaknesses) Weights() []float64 {
+	weights := make([]float64, len(w.Patterns))
+	avail, taken, secs := 0, 0, 0.0
+	for _, p := range w.Patterns {
+		avail += p.Available
+		taken += p.Taken
+		secs += p.Time.Seconds()
+	}
+	if avail == 0 || taken == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		return weights
+	}
+	rate, mean := float64(taken)/float64(avail), secs/float64(taken)
+	for i, p := range w.Patterns {
+		pRate := (float64(p.Taken) + rate*weaknessPrior) / (float64(p.Available) + weaknessPrior)
+		pMean := (p.Time.Seconds() + mean*weaknessPrior) / (float64(p.Taken) + weaknessPrior)
+		weights[i] = rate / pRate * pMean / mean
+	}
+	return weights
+}
+
+// Weakest returns the pattern the player is weakest at, or false if there are
+// no statistics yet.
+func (w *Weaknesses) Weakest() (PatternStats, bool) {
+	weights := w.Weights()
+	best := -1
+	for i, wt := range weights {
+		if w.Patterns[i].Available > 0 && (best < 0 || wt > weights[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return PatternStats{}, false
+	}
+	return w.Patterns[best], true
+}
+
+// TrainingDeal is a DealPolicy for practice.  It deals whichever of the next
+// few cards in the deck makes the most matches of the patterns the player is
+// weakest at.
+type TrainingDeal struct {
+	Weights   []float64 // weight of each pattern, as from Weaknesses.Weights
+	Lookahead int       // number of cards to choose from
+}
+
+// Pick returns the index of the card among the first Lookahead in deck whose
+// new matches with the field have the highest total weight, preferring
+// earlier cards.  A match weighs the mean of the weights of its patterns.
+func (p TrainingDeal) Pick(t *TriGo, deck []int) int {
+	return bestPick(t, deck, p.Lookahead, func(s *TriGo) float64 {
+		last := len(s.state.Field) - 1
+		weight := 0.0
+		s.eachMatch(func(slots []int) bool {
+			if slots[len(slots)-1] != last {
+				return true
+			}
+			cards := make([]int, len(slots))
+			for j, f := range slots {
+				cards[j] = s.state.Field[f]
+			}
+			weight += p.matchWeight(s.patterns(cards))
+			return true
+		})
+		return weight
+	})
+}
+
+// matchWeight returns the mean weight of the given patterns.
+func (p TrainingDeal) matchWeight(pats []int) float64 {
+	if len(pats) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, i := range pats {
+		if i < len(p.Weights) {
+			sum += p.Weights[i]
+		} else {
+			sum++
+		}
+	}
+	return sum / float64(len(pats))
+}
@@ -0,0 +1,97 @@
+package trigo
+
+import (
+	"testing"
+	"time"
+)
+
+// patternReport returns a report in which every pattern of a standard game
+// has 20 matches available, 10 taken in 5s each, except as changed by fn.
+func patternReport(fn func(p *PatternStats)) Report {
+	r := Report{Patterns: make([]PatternStats, 8)}
+	for i := range r.Patterns {
+		r.Patterns[i] = PatternStats{Attr: i / 2, AllDiff: i%2 == 1, Available: 20, Taken: 10, Time: 50 * time.Second}
+		fn(&r.Patterns[i])
+	}
+	return r
+}
+
+func TestWeights(t *testing.T) {
+	w := Weaknesses{}
+	if _, ok := w.Weakest(); ok {
+		t.Error("weakest pattern found without statistics")
+	}
+	w.Add(patternReport(func(p *PatternStats) {}))
+	for i, wt := range w.Weights() {
+		if wt != 1 {
+			t.Errorf("even play: pattern %d weighs %v, want 1", i, wt)
+		}
+	}
+
+	// pattern 3 is missed more, and pattern 6 taken more slowly
+	uneven := patternReport(func(p *PatternStats) {
+		switch {
+		case p.Attr == 1 && p.AllDiff:
+			p.Taken, p.Time = 2, 10*time.Second
+		case p.Attr == 3 && !p.AllDiff:
+			p.Time = 150 * time.Second
+		}
+	})
+	prev := w.Weights()
+	for round := 0; round < 3; round++ {
+		w.Add(uneven)
+		weights := w.Weights()
+		for i, wt := range weights {
+			if i == 3 || i == 6 {
+				if wt <= prev[i] {
+					t.Errorf("round %d: weak pattern %d weighs %v, down from %v", round, i, wt, prev[i])
+				}
+			} else if wt >= weights[3] || wt >= weights[6] {
+				t.Errorf("round %d: pattern %d weighs %v, more than a weak pattern %v", round, i, wt, weights)
+			}
+		}
+		prev = weights
+	}
+	if p, ok := w.Weakest(); !ok || p.Attr != 1 || !p.AllDiff {
+		t.Errorf("weakest is %+v, want attribute 1 all different", p)
+	}
+	if n := w.Patterns[0].Available; n != 80 {
+		t.Errorf("%d matches available in 4 games, want 80", n)
+	}
+}
+
+func TestTrainingDeal(t *testing.T) {
+	// favour matches whose first attribute is all the same, and count them on
+	// the field after every deal of a game
+	weights := []float64{10, 1, 1, 1, 1, 1, 1, 1}
+	share := func(p DealPolicy) float64 {
+		favoured, total := 0, 0
+		for seed := int64(1); seed <= 20; seed++ {
+			tri := NewStd()
+			tri.SetDealPolicy(p)
+			tri.Seed(seed)
+			tri.Shuffle()
+			tri.Deal()
+			for !tri.Over() {
+				for _, m := range fieldMatches(tri) {
+					total++
+					if tri.patterns(m)[0] == 0 {
+						favoured++
+					}
+				}
+				tri.Claim(tri.FindMatch())
+				tri.Deal()
+			}
+		}
+		return float64(favoured) / float64(total)
+	}
+	ordered, trained := share(nil), share(TrainingDeal{Weights: weights, Lookahead: 12})
+	if trained < ordered+0.05 {
+		t.Errorf("%.2f of matches favoured when training, want 0.05 more than the %.2f dealt in order", trained, ordered)
+	}
+
+	tri := clockedGame(&SimClock{}, 1)
+	if i := (TrainingDeal{Weights: weights}).Pick(tri, tri.state.Deck); i != 0 {
+		t.Errorf("no lookahead picked card %d, want 0", i)
+	}
+}